@@ -0,0 +1,149 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	subtleaead "github.com/google/tink/go/subtle/aead"
+	"github.com/google/tink/go/subtle/random"
+	gsvpb "github.com/google/tink/proto/aes_gcm_siv_go_proto"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+)
+
+const (
+	// AESGCMSIVKeyVersion is the maximum version of AES-GCM-SIV keys accepted by this key manager.
+	AESGCMSIVKeyVersion = 0
+	// AESGCMSIVTypeURL is the type URL of AES-GCM-SIV keys that is supported by this key manager.
+	AESGCMSIVTypeURL = "type.googleapis.com/google.crypto.tink.AesGcmSivKey"
+)
+
+var errInvalidAESGCMSIVKey = errors.New("aes_gcm_siv_key_manager: invalid key")
+var errInvalidAESGCMSIVKeyFormat = errors.New("aes_gcm_siv_key_manager: invalid key format")
+
+// AESGCMSIVKeyManager generates AesGcmSivKey keys and produces instances of
+// AESGCMSIV, a nonce-misuse-resistant AEAD (RFC 8452).
+type AESGCMSIVKeyManager struct{}
+
+// NewAESGCMSIVKeyManager creates a new AESGCMSIVKeyManager.
+func NewAESGCMSIVKeyManager() *AESGCMSIVKeyManager {
+	return new(AESGCMSIVKeyManager)
+}
+
+// GetPrimitiveFromSerializedKey creates a new AESGCMSIV subtle from the given
+// serialized AesGcmSivKey.
+func (km *AESGCMSIVKeyManager) GetPrimitiveFromSerializedKey(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidAESGCMSIVKey
+	}
+	key := new(gsvpb.AesGcmSivKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidAESGCMSIVKey
+	}
+	return km.GetPrimitiveFromKey(key)
+}
+
+// GetPrimitiveFromKey creates a new AESGCMSIV subtle from the given AesGcmSivKey.
+func (km *AESGCMSIVKeyManager) GetPrimitiveFromKey(key *gsvpb.AesGcmSivKey) (interface{}, error) {
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	return subtleaead.NewAESGCMSIV(key.KeyValue)
+}
+
+// NewKeyFromSerializedKeyFormat creates a new key according to the given
+// serialized AesGcmSivKeyFormat.
+func (km *AESGCMSIVKeyManager) NewKeyFromSerializedKeyFormat(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidAESGCMSIVKeyFormat
+	}
+	keyFormat := new(gsvpb.AesGcmSivKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidAESGCMSIVKeyFormat
+	}
+	return km.NewKeyFromKeyFormat(keyFormat)
+}
+
+// NewKeyFromKeyFormat creates a new key according to the given AesGcmSivKeyFormat.
+func (km *AESGCMSIVKeyManager) NewKeyFromKeyFormat(keyFormat proto.Message) (proto.Message, error) {
+	format, ok := keyFormat.(*gsvpb.AesGcmSivKeyFormat)
+	if !ok {
+		return nil, errInvalidAESGCMSIVKeyFormat
+	}
+	if err := km.validateKeyFormat(format); err != nil {
+		return nil, err
+	}
+	return &gsvpb.AesGcmSivKey{
+		Version:  AESGCMSIVKeyVersion,
+		KeyValue: random.GetRandomBytes(format.KeySize),
+	}, nil
+}
+
+// NewAESGCMSIVKey generates a new AesGcmSivKey with the given key size, in bytes.
+func (km *AESGCMSIVKeyManager) NewAESGCMSIVKey(keySize uint32) (*gsvpb.AesGcmSivKey, error) {
+	m, err := km.NewKeyFromKeyFormat(&gsvpb.AesGcmSivKeyFormat{KeySize: keySize, Version: AESGCMSIVKeyVersion})
+	if err != nil {
+		return nil, err
+	}
+	return m.(*gsvpb.AesGcmSivKey), nil
+}
+
+// NewKeyData creates a new KeyData according to specification in the given
+// serialized AesGcmSivKeyFormat.
+func (km *AESGCMSIVKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKeyFromSerializedKeyFormat(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, fmt.Errorf("aes_gcm_siv_key_manager: %v", err)
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         AESGCMSIVTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport returns true iff this key manager supports key type typeURL.
+func (km *AESGCMSIVKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == AESGCMSIVTypeURL
+}
+
+// GetKeyType returns the key type of keys managed by this key manager.
+func (km *AESGCMSIVKeyManager) GetKeyType() string {
+	return AESGCMSIVTypeURL
+}
+
+func (km *AESGCMSIVKeyManager) validateKey(key *gsvpb.AesGcmSivKey) error {
+	if key.Version != AESGCMSIVKeyVersion {
+		return errInvalidAESGCMSIVKey
+	}
+	keySize := uint32(len(key.KeyValue))
+	if keySize != 16 && keySize != 32 {
+		return errInvalidAESGCMSIVKey
+	}
+	return nil
+}
+
+func (km *AESGCMSIVKeyManager) validateKeyFormat(format *gsvpb.AesGcmSivKeyFormat) error {
+	if format.KeySize != 16 && format.KeySize != 32 {
+		return errInvalidAESGCMSIVKeyFormat
+	}
+	return nil
+}
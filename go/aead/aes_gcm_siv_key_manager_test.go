@@ -0,0 +1,222 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/subtle/random"
+
+	subtleaead "github.com/google/tink/go/subtle/aead"
+	gsvpb "github.com/google/tink/proto/aes_gcm_siv_go_proto"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+)
+
+func TestNewAESGCMSIVKeyManager(t *testing.T) {
+	km := aead.NewAESGCMSIVKeyManager()
+	if km == nil {
+		t.Errorf("NewAESGCMSIVKeyManager() returns nil")
+	}
+}
+
+func TestAESGCMSIVGetPrimitive(t *testing.T) {
+	for _, keySize := range []uint32{16, 32} {
+		km := aead.NewAESGCMSIVKeyManager()
+		key, err := km.NewAESGCMSIVKey(keySize)
+		if err != nil {
+			t.Fatalf("km.NewAESGCMSIVKey(%d) = _, %v; want _, nil", keySize, err)
+		}
+		p, err := km.GetPrimitiveFromKey(key)
+		if err != nil {
+			t.Errorf("km.GetPrimitiveFromKey(%v) = _, %v; want _, nil", key, err)
+		}
+		if err := validateAESGCMSIVPrimitive(p, key); err != nil {
+			t.Errorf("validateAESGCMSIVPrimitive(p, key) = %v; want nil", err)
+		}
+
+		serializedKey, _ := proto.Marshal(key)
+		p, err = km.GetPrimitiveFromSerializedKey(serializedKey)
+		if err != nil {
+			t.Errorf("km.GetPrimitiveFromSerializedKey(%v) = _, %v; want _, nil", serializedKey, err)
+		}
+		if err := validateAESGCMSIVPrimitive(p, key); err != nil {
+			t.Errorf("validateAESGCMSIVPrimitive(p, key) = %v; want nil", err)
+		}
+	}
+}
+
+// TestAESGCMSIVEncryptDecryptRoundTrip checks the basic Encrypt/Decrypt round
+// trip through the key manager's primitive. The public AEAD interface draws
+// its own random nonce, so it cannot force the nonce reuse needed to
+// exercise the nonce-misuse-resistance property itself; that is covered by
+// the white-box tests in go/subtle/aead, which call seal/open directly with
+// an explicit, repeated nonce.
+func TestAESGCMSIVEncryptDecryptRoundTrip(t *testing.T) {
+	km := aead.NewAESGCMSIVKeyManager()
+	key, err := km.NewAESGCMSIVKey(32)
+	if err != nil {
+		t.Fatalf("km.NewAESGCMSIVKey(32) = _, %v; want _, nil", err)
+	}
+	p, err := km.GetPrimitiveFromKey(key)
+	if err != nil {
+		t.Fatalf("km.GetPrimitiveFromKey(%v) = _, %v; want _, nil", key, err)
+	}
+	cipher := p.(*subtleaead.AESGCMSIV)
+
+	pt := random.GetRandomBytes(64)
+	aad := random.GetRandomBytes(16)
+	ct, err := cipher.Encrypt(pt, aad)
+	if err != nil {
+		t.Fatalf("cipher.Encrypt() = _, %v; want _, nil", err)
+	}
+	decrypted, err := cipher.Decrypt(ct, aad)
+	if err != nil {
+		t.Fatalf("cipher.Decrypt() = _, %v; want _, nil", err)
+	}
+	if !bytes.Equal(decrypted, pt) {
+		t.Errorf("decrypted plaintext does not match original")
+	}
+}
+
+func TestAESGCMSIVGetPrimitiveWithInvalidKeys(t *testing.T) {
+	km := aead.NewAESGCMSIVKeyManager()
+	for _, key := range genInvalidAESGCMSIVKeys() {
+		if _, err := km.GetPrimitiveFromKey(key); err == nil {
+			t.Errorf("km.GetPrimitiveFromKey(%v) = _, nil; want _, err", key)
+		}
+		serializedKey, _ := proto.Marshal(key)
+		if _, err := km.GetPrimitiveFromSerializedKey(serializedKey); err == nil {
+			t.Errorf("km.GetPrimitiveFromSerializedKey(%v) = _, nil; want _, err", serializedKey)
+		}
+	}
+}
+
+func TestAESGCMSIVNewKeyWithInvalidFormat(t *testing.T) {
+	km := aead.NewAESGCMSIVKeyManager()
+	for _, keySize := range []uint32{0, 15, 17, 24, 33} {
+		format := &gsvpb.AesGcmSivKeyFormat{KeySize: keySize}
+		serializedFormat, _ := proto.Marshal(format)
+		if _, err := km.NewKeyFromSerializedKeyFormat(serializedFormat); err == nil {
+			t.Errorf("km.NewKeyFromSerializedKeyFormat(keySize=%d) = _, nil; want _, err", keySize)
+		}
+	}
+	if _, err := km.NewKeyFromSerializedKeyFormat(nil); err == nil {
+		t.Errorf("km.NewKeyFromSerializedKeyFormat(nil) = _, nil; want _, err")
+	}
+}
+
+func TestAESGCMSIVNewKeyData(t *testing.T) {
+	km := aead.NewAESGCMSIVKeyManager()
+	format := &gsvpb.AesGcmSivKeyFormat{KeySize: 32}
+	serializedFormat, _ := proto.Marshal(format)
+	kd, err := km.NewKeyData(serializedFormat)
+	if err != nil {
+		t.Fatalf("km.NewKeyData(%v) = _, %v; want _, nil", serializedFormat, err)
+	}
+	if kd.TypeUrl != aead.AESGCMSIVTypeURL {
+		t.Errorf("TypeUrl: %v != %v", kd.TypeUrl, aead.AESGCMSIVTypeURL)
+	}
+	if kd.KeyMaterialType != tinkpb.KeyData_SYMMETRIC {
+		t.Errorf("KeyMaterialType: %v != SYMMETRIC", kd.KeyMaterialType)
+	}
+	key := new(gsvpb.AesGcmSivKey)
+	if err := proto.Unmarshal(kd.Value, key); err != nil {
+		t.Errorf("proto.Unmarshal(%v, key) = %v; want nil", kd.Value, err)
+	}
+	if err := validateAESGCMSIVKey(key); err != nil {
+		t.Errorf("validateAESGCMSIVKey(%v) = %v; want nil", key, err)
+	}
+}
+
+func TestAESGCMSIVDoesSupport(t *testing.T) {
+	km := aead.NewAESGCMSIVKeyManager()
+	if !km.DoesSupport(aead.AESGCMSIVTypeURL) {
+		t.Errorf("AESGCMSIVKeyManager must support %s", aead.AESGCMSIVTypeURL)
+	}
+	if km.DoesSupport("some bad type") {
+		t.Errorf("AESGCMSIVKeyManager must only support %s", aead.AESGCMSIVTypeURL)
+	}
+}
+
+func TestAESGCMSIVGetKeyType(t *testing.T) {
+	km := aead.NewAESGCMSIVKeyManager()
+	if kt := km.GetKeyType(); kt != aead.AESGCMSIVTypeURL {
+		t.Errorf("km.GetKeyType() = %s; want %s", kt, aead.AESGCMSIVTypeURL)
+	}
+}
+
+func genInvalidAESGCMSIVKeys() []*gsvpb.AesGcmSivKey {
+	return []*gsvpb.AesGcmSivKey{
+		// Bad key size.
+		{
+			Version:  aead.AESGCMSIVKeyVersion,
+			KeyValue: random.GetRandomBytes(17),
+		},
+		{
+			Version:  aead.AESGCMSIVKeyVersion,
+			KeyValue: random.GetRandomBytes(24),
+		},
+		{
+			Version:  aead.AESGCMSIVKeyVersion,
+			KeyValue: random.GetRandomBytes(33),
+		},
+		// Bad version.
+		{
+			Version:  aead.AESGCMSIVKeyVersion + 1,
+			KeyValue: random.GetRandomBytes(32),
+		},
+	}
+}
+
+func validateAESGCMSIVPrimitive(p interface{}, key *gsvpb.AesGcmSivKey) error {
+	cipher := p.(*subtleaead.AESGCMSIV)
+	if !bytes.Equal(cipher.Key, key.KeyValue) {
+		return fmt.Errorf("key and primitive don't match")
+	}
+
+	pt := random.GetRandomBytes(32)
+	aad := random.GetRandomBytes(32)
+	ct, err := cipher.Encrypt(pt, aad)
+	if err != nil {
+		return fmt.Errorf("encryption failed")
+	}
+	decrypted, err := cipher.Decrypt(ct, aad)
+	if err != nil {
+		return fmt.Errorf("decryption failed")
+	}
+	if !bytes.Equal(decrypted, pt) {
+		return fmt.Errorf("decryption failed")
+	}
+	return nil
+}
+
+func validateAESGCMSIVKey(key *gsvpb.AesGcmSivKey) error {
+	if key.Version != aead.AESGCMSIVKeyVersion {
+		return fmt.Errorf("incorrect key version: keyVersion != %d", aead.AESGCMSIVKeyVersion)
+	}
+	keySize := uint32(len(key.KeyValue))
+	if keySize != 16 && keySize != 32 {
+		return fmt.Errorf("incorrect key size: keySize != 16 or 32, got %d", keySize)
+	}
+	p, err := subtleaead.NewAESGCMSIV(key.KeyValue)
+	if err != nil {
+		return fmt.Errorf("invalid key: %v", key.KeyValue)
+	}
+	return validateAESGCMSIVPrimitive(p, key)
+}
@@ -0,0 +1,61 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"fmt"
+
+	"github.com/google/tink/go/keyset"
+	subtleaead "github.com/google/tink/go/subtle/aead"
+	"github.com/google/tink/go/tink"
+)
+
+// NewDerivedAEAD returns a tink.AEAD that derives its encryption key from
+// the DerivedXChaCha20Poly1305 master key held by handle, scoped to context.
+// Every call with the same context derives the same subkey, so callers can
+// register a single master key and hand out independently-scoped AEADs for
+// as many logical contexts (per-user, per-file, per-tenant, ...) as needed.
+func NewDerivedAEAD(handle *keyset.Handle, context string) (tink.AEAD, error) {
+	ps, err := handle.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("aead_factory: cannot obtain primitives: %v", err)
+	}
+	if ps.Primary == nil {
+		return nil, fmt.Errorf("aead_factory: no primary primitive")
+	}
+	primitive, ok := ps.Primary.Primitive.(*subtleaead.DerivedXChaCha20Poly1305)
+	if !ok {
+		return nil, fmt.Errorf("aead_factory: primary key is not a DerivedXChaCha20Poly1305 key")
+	}
+	return &scopedDerivedAEAD{primitive: primitive, context: context}, nil
+}
+
+// scopedDerivedAEAD adapts a DerivedXChaCha20Poly1305 primitive, whose
+// Encrypt/Decrypt take an explicit context, to the context-free tink.AEAD
+// interface by binding a single fixed context.
+type scopedDerivedAEAD struct {
+	primitive *subtleaead.DerivedXChaCha20Poly1305
+	context   string
+}
+
+// Encrypt implements the tink.AEAD interface.
+func (a *scopedDerivedAEAD) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	return a.primitive.Encrypt(plaintext, additionalData, a.context)
+}
+
+// Decrypt implements the tink.AEAD interface.
+func (a *scopedDerivedAEAD) Decrypt(ciphertext, additionalData []byte) ([]byte, error) {
+	return a.primitive.Decrypt(ciphertext, additionalData, a.context)
+}
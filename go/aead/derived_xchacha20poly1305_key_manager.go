@@ -0,0 +1,151 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	subtleaead "github.com/google/tink/go/subtle/aead"
+	"github.com/google/tink/go/subtle/random"
+	dxcppb "github.com/google/tink/proto/derived_xchacha20_poly1305_go_proto"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+)
+
+const (
+	// DerivedXChaCha20Poly1305KeyVersion is the maximum version of
+	// DerivedXChaCha20Poly1305 keys accepted by this key manager.
+	DerivedXChaCha20Poly1305KeyVersion = 0
+	// DerivedXChaCha20Poly1305TypeURL is the type URL of
+	// DerivedXChaCha20Poly1305 keys that is supported by this key manager.
+	DerivedXChaCha20Poly1305TypeURL = "type.googleapis.com/google.crypto.tink.DerivedXChaCha20Poly1305Key"
+
+	derivedXChaCha20Poly1305MasterKeySize = 32
+)
+
+var errInvalidDerivedXChaCha20Poly1305Key = errors.New("derived_xchacha20poly1305_key_manager: invalid key")
+var errInvalidDerivedXChaCha20Poly1305KeyFormat = errors.New("derived_xchacha20poly1305_key_manager: invalid key format")
+
+// DerivedXChaCha20Poly1305KeyManager generates DerivedXChaCha20Poly1305Key
+// keys and produces instances of DerivedXChaCha20Poly1305.
+type DerivedXChaCha20Poly1305KeyManager struct{}
+
+// NewDerivedXChaCha20Poly1305KeyManager creates a new
+// DerivedXChaCha20Poly1305KeyManager.
+func NewDerivedXChaCha20Poly1305KeyManager() *DerivedXChaCha20Poly1305KeyManager {
+	return new(DerivedXChaCha20Poly1305KeyManager)
+}
+
+// GetPrimitiveFromSerializedKey creates a new DerivedXChaCha20Poly1305
+// subtle from the given serialized DerivedXChaCha20Poly1305Key.
+func (km *DerivedXChaCha20Poly1305KeyManager) GetPrimitiveFromSerializedKey(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidDerivedXChaCha20Poly1305Key
+	}
+	key := new(dxcppb.DerivedXChaCha20Poly1305Key)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidDerivedXChaCha20Poly1305Key
+	}
+	return km.GetPrimitiveFromKey(key)
+}
+
+// GetPrimitiveFromKey creates a new DerivedXChaCha20Poly1305 subtle from the
+// given DerivedXChaCha20Poly1305Key.
+func (km *DerivedXChaCha20Poly1305KeyManager) GetPrimitiveFromKey(key *dxcppb.DerivedXChaCha20Poly1305Key) (interface{}, error) {
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	return subtleaead.NewDerivedXChaCha20Poly1305(key.MasterKey, key.Info)
+}
+
+// NewKeyFromSerializedKeyFormat creates a new key according to the given
+// serialized DerivedXChaCha20Poly1305KeyFormat.
+func (km *DerivedXChaCha20Poly1305KeyManager) NewKeyFromSerializedKeyFormat(serializedKeyFormat []byte) (proto.Message, error) {
+	keyFormat := new(dxcppb.DerivedXChaCha20Poly1305KeyFormat)
+	if len(serializedKeyFormat) > 0 {
+		if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+			return nil, errInvalidDerivedXChaCha20Poly1305KeyFormat
+		}
+	}
+	return km.NewKeyFromKeyFormat(keyFormat)
+}
+
+// NewKeyFromKeyFormat creates a new key according to the given
+// DerivedXChaCha20Poly1305KeyFormat. A nil keyFormat is treated as a request
+// for the default (empty info) format.
+func (km *DerivedXChaCha20Poly1305KeyManager) NewKeyFromKeyFormat(keyFormat proto.Message) (proto.Message, error) {
+	var info []byte
+	if keyFormat != nil {
+		format, ok := keyFormat.(*dxcppb.DerivedXChaCha20Poly1305KeyFormat)
+		if !ok {
+			return nil, errInvalidDerivedXChaCha20Poly1305KeyFormat
+		}
+		info = format.Info
+	}
+	return &dxcppb.DerivedXChaCha20Poly1305Key{
+		Version:   DerivedXChaCha20Poly1305KeyVersion,
+		MasterKey: random.GetRandomBytes(derivedXChaCha20Poly1305MasterKeySize),
+		Info:      info,
+	}, nil
+}
+
+// NewDerivedXChaCha20Poly1305Key generates a new
+// DerivedXChaCha20Poly1305Key with the given default info label.
+func (km *DerivedXChaCha20Poly1305KeyManager) NewDerivedXChaCha20Poly1305Key(info []byte) (*dxcppb.DerivedXChaCha20Poly1305Key, error) {
+	m, err := km.NewKeyFromKeyFormat(&dxcppb.DerivedXChaCha20Poly1305KeyFormat{Info: info})
+	if err != nil {
+		return nil, err
+	}
+	return m.(*dxcppb.DerivedXChaCha20Poly1305Key), nil
+}
+
+// NewKeyData creates a new KeyData according to specification in the given
+// serialized DerivedXChaCha20Poly1305KeyFormat.
+func (km *DerivedXChaCha20Poly1305KeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKeyFromSerializedKeyFormat(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, fmt.Errorf("derived_xchacha20poly1305_key_manager: %v", err)
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         DerivedXChaCha20Poly1305TypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport returns true iff this key manager supports key type typeURL.
+func (km *DerivedXChaCha20Poly1305KeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == DerivedXChaCha20Poly1305TypeURL
+}
+
+// GetKeyType returns the key type of keys managed by this key manager.
+func (km *DerivedXChaCha20Poly1305KeyManager) GetKeyType() string {
+	return DerivedXChaCha20Poly1305TypeURL
+}
+
+func (km *DerivedXChaCha20Poly1305KeyManager) validateKey(key *dxcppb.DerivedXChaCha20Poly1305Key) error {
+	if key.Version != DerivedXChaCha20Poly1305KeyVersion {
+		return errInvalidDerivedXChaCha20Poly1305Key
+	}
+	if len(key.MasterKey) != derivedXChaCha20Poly1305MasterKeySize {
+		return errInvalidDerivedXChaCha20Poly1305Key
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/subtle/random"
+
+	subtleaead "github.com/google/tink/go/subtle/aead"
+	dxcppb "github.com/google/tink/proto/derived_xchacha20_poly1305_go_proto"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+)
+
+func TestNewDerivedXChaCha20Poly1305KeyManager(t *testing.T) {
+	km := aead.NewDerivedXChaCha20Poly1305KeyManager()
+	if km == nil {
+		t.Errorf("NewDerivedXChaCha20Poly1305KeyManager() returns nil")
+	}
+}
+
+func TestDerivedXChaCha20Poly1305GetPrimitive(t *testing.T) {
+	km := aead.NewDerivedXChaCha20Poly1305KeyManager()
+	key, err := km.NewDerivedXChaCha20Poly1305Key([]byte("my-app"))
+	if err != nil {
+		t.Fatalf("km.NewDerivedXChaCha20Poly1305Key() = _, %v; want _, nil", err)
+	}
+	p, err := km.GetPrimitiveFromKey(key)
+	if err != nil {
+		t.Errorf("km.GetPrimitiveFromKey(%v) = _, %v; want _, nil", key, err)
+	}
+	if err := validateDerivedXChaCha20Poly1305Primitive(p, key); err != nil {
+		t.Errorf("validateDerivedXChaCha20Poly1305Primitive(p, key) = %v; want nil", err)
+	}
+
+	serializedKey, _ := proto.Marshal(key)
+	p, err = km.GetPrimitiveFromSerializedKey(serializedKey)
+	if err != nil {
+		t.Errorf("km.GetPrimitiveFromSerializedKey(%v) = _, %v; want _, nil", serializedKey, err)
+	}
+	if err := validateDerivedXChaCha20Poly1305Primitive(p, key); err != nil {
+		t.Errorf("validateDerivedXChaCha20Poly1305Primitive(p, key) = %v; want nil", err)
+	}
+}
+
+func TestDerivedXChaCha20Poly1305DifferentContextsDeriveDifferentKeys(t *testing.T) {
+	km := aead.NewDerivedXChaCha20Poly1305KeyManager()
+	key, err := km.NewDerivedXChaCha20Poly1305Key(nil)
+	if err != nil {
+		t.Fatalf("km.NewDerivedXChaCha20Poly1305Key() = _, %v; want _, nil", err)
+	}
+	p, err := km.GetPrimitiveFromKey(key)
+	if err != nil {
+		t.Fatalf("km.GetPrimitiveFromKey(%v) = _, %v; want _, nil", key, err)
+	}
+	cipher := p.(*subtleaead.DerivedXChaCha20Poly1305)
+
+	pt := random.GetRandomBytes(32)
+	aad := random.GetRandomBytes(16)
+	ctA, err := cipher.Encrypt(pt, aad, "tenant-a")
+	if err != nil {
+		t.Fatalf("cipher.Encrypt(context=tenant-a) = _, %v; want _, nil", err)
+	}
+	if _, err := cipher.Decrypt(ctA, aad, "tenant-b"); err == nil {
+		t.Errorf("cipher.Decrypt(ctA, context=tenant-b) = _, nil; want _, err")
+	}
+	decrypted, err := cipher.Decrypt(ctA, aad, "tenant-a")
+	if err != nil {
+		t.Fatalf("cipher.Decrypt(ctA, context=tenant-a) = _, %v; want _, nil", err)
+	}
+	if !bytes.Equal(decrypted, pt) {
+		t.Errorf("decrypted plaintext does not match original")
+	}
+}
+
+func TestDerivedXChaCha20Poly1305GetPrimitiveWithInvalidKeys(t *testing.T) {
+	km := aead.NewDerivedXChaCha20Poly1305KeyManager()
+	for _, key := range genInvalidDerivedXChaCha20Poly1305Keys() {
+		if _, err := km.GetPrimitiveFromKey(key); err == nil {
+			t.Errorf("km.GetPrimitiveFromKey(%v) = _, nil; want _, err", key)
+		}
+		serializedKey, _ := proto.Marshal(key)
+		if _, err := km.GetPrimitiveFromSerializedKey(serializedKey); err == nil {
+			t.Errorf("km.GetPrimitiveFromSerializedKey(%v) = _, nil; want _, err", serializedKey)
+		}
+	}
+}
+
+func TestDerivedXChaCha20Poly1305NewKeyData(t *testing.T) {
+	km := aead.NewDerivedXChaCha20Poly1305KeyManager()
+	kd, err := km.NewKeyData(nil)
+	if err != nil {
+		t.Fatalf("km.NewKeyData(nil) = _, %v; want _, nil", err)
+	}
+	if kd.TypeUrl != aead.DerivedXChaCha20Poly1305TypeURL {
+		t.Errorf("TypeUrl: %v != %v", kd.TypeUrl, aead.DerivedXChaCha20Poly1305TypeURL)
+	}
+	if kd.KeyMaterialType != tinkpb.KeyData_SYMMETRIC {
+		t.Errorf("KeyMaterialType: %v != SYMMETRIC", kd.KeyMaterialType)
+	}
+	key := new(dxcppb.DerivedXChaCha20Poly1305Key)
+	if err := proto.Unmarshal(kd.Value, key); err != nil {
+		t.Errorf("proto.Unmarshal(%v, key) = %v; want nil", kd.Value, err)
+	}
+	if err := validateDerivedXChaCha20Poly1305Key(key); err != nil {
+		t.Errorf("validateDerivedXChaCha20Poly1305Key(%v) = %v; want nil", key, err)
+	}
+}
+
+func TestDerivedXChaCha20Poly1305DoesSupport(t *testing.T) {
+	km := aead.NewDerivedXChaCha20Poly1305KeyManager()
+	if !km.DoesSupport(aead.DerivedXChaCha20Poly1305TypeURL) {
+		t.Errorf("DerivedXChaCha20Poly1305KeyManager must support %s", aead.DerivedXChaCha20Poly1305TypeURL)
+	}
+	if km.DoesSupport("some bad type") {
+		t.Errorf("DerivedXChaCha20Poly1305KeyManager must only support %s", aead.DerivedXChaCha20Poly1305TypeURL)
+	}
+}
+
+func genInvalidDerivedXChaCha20Poly1305Keys() []*dxcppb.DerivedXChaCha20Poly1305Key {
+	return []*dxcppb.DerivedXChaCha20Poly1305Key{
+		// Bad master key size.
+		{
+			Version:   aead.DerivedXChaCha20Poly1305KeyVersion,
+			MasterKey: random.GetRandomBytes(31),
+		},
+		{
+			Version:   aead.DerivedXChaCha20Poly1305KeyVersion,
+			MasterKey: random.GetRandomBytes(33),
+		},
+		// Bad version.
+		{
+			Version:   aead.DerivedXChaCha20Poly1305KeyVersion + 1,
+			MasterKey: random.GetRandomBytes(32),
+		},
+	}
+}
+
+func validateDerivedXChaCha20Poly1305Primitive(p interface{}, key *dxcppb.DerivedXChaCha20Poly1305Key) error {
+	cipher := p.(*subtleaead.DerivedXChaCha20Poly1305)
+	if !bytes.Equal(cipher.MasterKey, key.MasterKey) {
+		return fmt.Errorf("master key and primitive don't match")
+	}
+
+	pt := random.GetRandomBytes(32)
+	aad := random.GetRandomBytes(32)
+	ct, err := cipher.Encrypt(pt, aad, "context")
+	if err != nil {
+		return fmt.Errorf("encryption failed")
+	}
+	decrypted, err := cipher.Decrypt(ct, aad, "context")
+	if err != nil {
+		return fmt.Errorf("decryption failed")
+	}
+	if !bytes.Equal(decrypted, pt) {
+		return fmt.Errorf("decryption failed")
+	}
+	return nil
+}
+
+func validateDerivedXChaCha20Poly1305Key(key *dxcppb.DerivedXChaCha20Poly1305Key) error {
+	if key.Version != aead.DerivedXChaCha20Poly1305KeyVersion {
+		return fmt.Errorf("incorrect key version: keyVersion != %d", aead.DerivedXChaCha20Poly1305KeyVersion)
+	}
+	if len(key.MasterKey) != 32 {
+		return fmt.Errorf("incorrect master key size: != 32")
+	}
+	p, err := subtleaead.NewDerivedXChaCha20Poly1305(key.MasterKey, key.Info)
+	if err != nil {
+		return fmt.Errorf("invalid key: %v", key.MasterKey)
+	}
+	return validateDerivedXChaCha20Poly1305Primitive(p, key)
+}
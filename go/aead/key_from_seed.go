@@ -0,0 +1,117 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+
+	gsvpb "github.com/google/tink/proto/aes_gcm_siv_go_proto"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+	xcppb "github.com/google/tink/proto/xchacha20_poly1305_go_proto"
+)
+
+// minSeedSize is the smallest seed this package will derive a key from. It
+// matches the output size of the HKDF-SHA256 extract step, below which the
+// seed cannot carry full-strength entropy into the derived key.
+const minSeedSize = 32
+
+// deriveKeyFromSeed derives keySize bytes of deterministic key material from
+// seed via HKDF-SHA256(seed, salt=typeURL, info=info). Using the type URL as
+// the HKDF salt means that two key types derived from the same seed are
+// independent of each other, even though they share the seed.
+func deriveKeyFromSeed(seed []byte, typeURL, info string, keySize int) ([]byte, error) {
+	if len(seed) < minSeedSize {
+		return nil, fmt.Errorf("aead: seed too short; want >= %d bytes, got %d", minSeedSize, len(seed))
+	}
+	reader := hkdf.New(sha256.New, seed, []byte(typeURL), []byte(info))
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(reader, key); err != nil {
+		return nil, fmt.Errorf("aead: failed to derive key from seed: %v", err)
+	}
+	return key, nil
+}
+
+// NewKeyFromSeed deterministically derives a new XChaCha20Poly1305Key from
+// seed instead of drawing fresh randomness from random.GetRandomBytes. This
+// lets operators reproduce identical keysets across environments from a
+// stored seed (e.g. a KMS-wrapped root) without ever persisting the raw AEAD
+// key, and enables golden-value tests.
+func (km *XChaCha20Poly1305KeyManager) NewKeyFromSeed(seed []byte) (proto.Message, error) {
+	keyValue, err := deriveKeyFromSeed(seed, XChaCha20Poly1305TypeURL, "tink-xchacha20poly1305-key", chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, err
+	}
+	return &xcppb.XChaCha20Poly1305Key{
+		Version:  XChaCha20Poly1305KeyVersion,
+		KeyValue: keyValue,
+	}, nil
+}
+
+// NewKeyDataFromSeed is the KeyData-producing counterpart of NewKeyFromSeed.
+func (km *XChaCha20Poly1305KeyManager) NewKeyDataFromSeed(seed []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKeyFromSeed(seed)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, fmt.Errorf("aead: %v", err)
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         XChaCha20Poly1305TypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// NewKeyFromSeed deterministically derives a new AesGcmSivKey of the given
+// key size (16 or 32 bytes) from seed. See
+// XChaCha20Poly1305KeyManager.NewKeyFromSeed for the rationale.
+func (km *AESGCMSIVKeyManager) NewKeyFromSeed(seed []byte, keySize uint32) (proto.Message, error) {
+	if keySize != 16 && keySize != 32 {
+		return nil, errInvalidAESGCMSIVKeyFormat
+	}
+	keyValue, err := deriveKeyFromSeed(seed, AESGCMSIVTypeURL, "tink-aes-gcm-siv-key", int(keySize))
+	if err != nil {
+		return nil, err
+	}
+	return &gsvpb.AesGcmSivKey{
+		Version:  AESGCMSIVKeyVersion,
+		KeyValue: keyValue,
+	}, nil
+}
+
+// NewKeyDataFromSeed is the KeyData-producing counterpart of NewKeyFromSeed.
+func (km *AESGCMSIVKeyManager) NewKeyDataFromSeed(seed []byte, keySize uint32) (*tinkpb.KeyData, error) {
+	key, err := km.NewKeyFromSeed(seed, keySize)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, fmt.Errorf("aead: %v", err)
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         AESGCMSIVTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
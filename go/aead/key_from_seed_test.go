@@ -0,0 +1,111 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/subtle/random"
+
+	xcppb "github.com/google/tink/proto/xchacha20_poly1305_go_proto"
+)
+
+func TestXChaCha20Poly1305NewKeyFromSeedIsDeterministic(t *testing.T) {
+	km := aead.NewXChaCha20Poly1305KeyManager()
+	seed := random.GetRandomBytes(32)
+
+	m1, err := km.NewKeyFromSeed(seed)
+	if err != nil {
+		t.Fatalf("km.NewKeyFromSeed() = _, %v; want _, nil", err)
+	}
+	m2, err := km.NewKeyFromSeed(seed)
+	if err != nil {
+		t.Fatalf("km.NewKeyFromSeed() = _, %v; want _, nil", err)
+	}
+	key1 := m1.(*xcppb.XChaCha20Poly1305Key)
+	key2 := m2.(*xcppb.XChaCha20Poly1305Key)
+	if !bytes.Equal(key1.KeyValue, key2.KeyValue) {
+		t.Errorf("two calls to NewKeyFromSeed with the same seed produced different keys")
+	}
+}
+
+func TestXChaCha20Poly1305NewKeyFromSeedDiffersAcrossTypeURLs(t *testing.T) {
+	seed := random.GetRandomBytes(32)
+
+	xcKM := aead.NewXChaCha20Poly1305KeyManager()
+	xcKey, err := xcKM.NewKeyFromSeed(seed)
+	if err != nil {
+		t.Fatalf("xcKM.NewKeyFromSeed() = _, %v; want _, nil", err)
+	}
+
+	gsvKM := aead.NewAESGCMSIVKeyManager()
+	gsvKey, err := gsvKM.NewKeyFromSeed(seed, 32)
+	if err != nil {
+		t.Fatalf("gsvKM.NewKeyFromSeed() = _, %v; want _, nil", err)
+	}
+
+	xcKeyValue := xcKey.(*xcppb.XChaCha20Poly1305Key).KeyValue
+	if bytes.Equal(xcKeyValue, gsvKey.(interface{ GetKeyValue() []byte }).GetKeyValue()) {
+		t.Errorf("same seed produced identical key material for two different key types")
+	}
+}
+
+func TestXChaCha20Poly1305NewKeyFromSeedRejectsShortSeed(t *testing.T) {
+	km := aead.NewXChaCha20Poly1305KeyManager()
+	if _, err := km.NewKeyFromSeed(random.GetRandomBytes(31)); err == nil {
+		t.Errorf("km.NewKeyFromSeed() with a 31-byte seed = _, nil; want _, err")
+	}
+}
+
+func TestXChaCha20Poly1305NewKeyDataFromSeed(t *testing.T) {
+	km := aead.NewXChaCha20Poly1305KeyManager()
+	seed := random.GetRandomBytes(32)
+	kd, err := km.NewKeyDataFromSeed(seed)
+	if err != nil {
+		t.Fatalf("km.NewKeyDataFromSeed() = _, %v; want _, nil", err)
+	}
+	if kd.TypeUrl != aead.XChaCha20Poly1305TypeURL {
+		t.Errorf("TypeUrl: %v != %v", kd.TypeUrl, aead.XChaCha20Poly1305TypeURL)
+	}
+	p, err := km.GetPrimitiveFromSerializedKey(kd.Value)
+	if err != nil {
+		t.Fatalf("km.GetPrimitiveFromSerializedKey() = _, %v; want _, nil", err)
+	}
+	if err := validateXChaCha20Poly1305Primitive(p, &xcppb.XChaCha20Poly1305Key{
+		Version:  aead.XChaCha20Poly1305KeyVersion,
+		KeyValue: mustSeedKeyValue(t, km, seed),
+	}); err != nil {
+		t.Errorf("validateXChaCha20Poly1305Primitive() = %v; want nil", err)
+	}
+}
+
+func TestAESGCMSIVNewKeyFromSeedRejectsBadKeySize(t *testing.T) {
+	km := aead.NewAESGCMSIVKeyManager()
+	seed := random.GetRandomBytes(32)
+	if _, err := km.NewKeyFromSeed(seed, 24); err == nil {
+		t.Errorf("km.NewKeyFromSeed(seed, 24) = _, nil; want _, err")
+	}
+}
+
+func mustSeedKeyValue(t *testing.T, km *aead.XChaCha20Poly1305KeyManager, seed []byte) []byte {
+	t.Helper()
+	m, err := km.NewKeyFromSeed(seed)
+	if err != nil {
+		t.Fatalf("km.NewKeyFromSeed() = _, %v; want _, nil", err)
+	}
+	return m.(*xcppb.XChaCha20Poly1305Key).KeyValue
+}
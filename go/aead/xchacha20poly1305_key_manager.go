@@ -0,0 +1,124 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/crypto/chacha20poly1305"
+
+	subtleaead "github.com/google/tink/go/subtle/aead"
+	"github.com/google/tink/go/subtle/random"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+	xcppb "github.com/google/tink/proto/xchacha20_poly1305_go_proto"
+)
+
+const (
+	// XChaCha20Poly1305KeyVersion is the maximum version of XChaCha20Poly1305 keys accepted by this key manager.
+	XChaCha20Poly1305KeyVersion = 0
+	// XChaCha20Poly1305TypeURL is the type URL of XChaCha20Poly1305 keys that is supported by this key manager.
+	XChaCha20Poly1305TypeURL = "type.googleapis.com/google.crypto.tink.XChaCha20Poly1305Key"
+)
+
+var errInvalidXChaCha20Poly1305Key = errors.New("xchacha20poly1305_key_manager: invalid key")
+
+// XChaCha20Poly1305KeyManager generates XChaCha20Poly1305Key keys and
+// produces instances of XChaCha20Poly1305.
+type XChaCha20Poly1305KeyManager struct{}
+
+// NewXChaCha20Poly1305KeyManager creates a new XChaCha20Poly1305KeyManager.
+func NewXChaCha20Poly1305KeyManager() *XChaCha20Poly1305KeyManager {
+	return new(XChaCha20Poly1305KeyManager)
+}
+
+// GetPrimitiveFromSerializedKey creates a new XChaCha20Poly1305 subtle from
+// the given serialized XChaCha20Poly1305Key.
+func (km *XChaCha20Poly1305KeyManager) GetPrimitiveFromSerializedKey(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidXChaCha20Poly1305Key
+	}
+	key := new(xcppb.XChaCha20Poly1305Key)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidXChaCha20Poly1305Key
+	}
+	return km.GetPrimitiveFromKey(key)
+}
+
+// GetPrimitiveFromKey creates a new XChaCha20Poly1305 subtle from the given XChaCha20Poly1305Key.
+func (km *XChaCha20Poly1305KeyManager) GetPrimitiveFromKey(key *xcppb.XChaCha20Poly1305Key) (interface{}, error) {
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	return subtleaead.NewXChaCha20Poly1305(key.KeyValue)
+}
+
+// NewKeyFromSerializedKeyFormat creates a new key according to the given
+// serialized XChaCha20Poly1305KeyFormat. XChaCha20Poly1305 keys have no
+// format parameters, so a nil/empty serializedKeyFormat is accepted.
+func (km *XChaCha20Poly1305KeyManager) NewKeyFromSerializedKeyFormat(serializedKeyFormat []byte) (proto.Message, error) {
+	return km.NewKeyFromKeyFormat(nil)
+}
+
+// NewKeyFromKeyFormat creates a new XChaCha20Poly1305Key. keyFormat is
+// ignored, since XChaCha20Poly1305 keys have no format parameters; it is
+// accepted (including nil) to satisfy the KeyManager interface.
+func (km *XChaCha20Poly1305KeyManager) NewKeyFromKeyFormat(keyFormat proto.Message) (proto.Message, error) {
+	return km.NewXChaCha20Poly1305Key(), nil
+}
+
+// NewXChaCha20Poly1305Key generates a new XChaCha20Poly1305Key.
+func (km *XChaCha20Poly1305KeyManager) NewXChaCha20Poly1305Key() *xcppb.XChaCha20Poly1305Key {
+	return &xcppb.XChaCha20Poly1305Key{
+		Version:  XChaCha20Poly1305KeyVersion,
+		KeyValue: random.GetRandomBytes(chacha20poly1305.KeySize),
+	}
+}
+
+// NewKeyData creates a new KeyData. serializedKeyFormat is ignored, since
+// XChaCha20Poly1305 keys have no format parameters.
+func (km *XChaCha20Poly1305KeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key := km.NewXChaCha20Poly1305Key()
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, fmt.Errorf("xchacha20poly1305_key_manager: %v", err)
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         XChaCha20Poly1305TypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport returns true iff this key manager supports key type typeURL.
+func (km *XChaCha20Poly1305KeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == XChaCha20Poly1305TypeURL
+}
+
+// GetKeyType returns the key type of keys managed by this key manager.
+func (km *XChaCha20Poly1305KeyManager) GetKeyType() string {
+	return XChaCha20Poly1305TypeURL
+}
+
+func (km *XChaCha20Poly1305KeyManager) validateKey(key *xcppb.XChaCha20Poly1305Key) error {
+	if key.Version != XChaCha20Poly1305KeyVersion {
+		return errInvalidXChaCha20Poly1305Key
+	}
+	if uint32(len(key.KeyValue)) != chacha20poly1305.KeySize {
+		return errInvalidXChaCha20Poly1305Key
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/hybrid"
+	"github.com/google/tink/go/subtle/random"
+
+	subtlehybrid "github.com/google/tink/go/subtle/hybrid"
+	xcphpb "github.com/google/tink/proto/xchacha20_poly1305_hkdf_hybrid_go_proto"
+)
+
+func privateKeyManager(t *testing.T) registry.PrivateKeyManager {
+	t.Helper()
+	km, err := registry.GetKeyManager(hybrid.XChaCha20Poly1305HKDFHybridPrivateKeyTypeURL)
+	if err != nil {
+		t.Fatalf("registry.GetKeyManager(%s) = _, %v; want _, nil", hybrid.XChaCha20Poly1305HKDFHybridPrivateKeyTypeURL, err)
+	}
+	pkm, ok := km.(registry.PrivateKeyManager)
+	if !ok {
+		t.Fatalf("key manager for %s does not implement PrivateKeyManager", hybrid.XChaCha20Poly1305HKDFHybridPrivateKeyTypeURL)
+	}
+	return pkm
+}
+
+func TestXChaCha20Poly1305HKDFHybridKeyManagersAreRegistered(t *testing.T) {
+	if _, err := registry.GetKeyManager(hybrid.XChaCha20Poly1305HKDFHybridPublicKeyTypeURL); err != nil {
+		t.Errorf("registry.GetKeyManager(%s) = _, %v; want _, nil", hybrid.XChaCha20Poly1305HKDFHybridPublicKeyTypeURL, err)
+	}
+	if _, err := registry.GetKeyManager(hybrid.XChaCha20Poly1305HKDFHybridPrivateKeyTypeURL); err != nil {
+		t.Errorf("registry.GetKeyManager(%s) = _, %v; want _, nil", hybrid.XChaCha20Poly1305HKDFHybridPrivateKeyTypeURL, err)
+	}
+}
+
+func TestXChaCha20Poly1305HKDFHybridRoundTripThroughKeyManagers(t *testing.T) {
+	privKM := privateKeyManager(t)
+	pubKM, err := registry.GetKeyManager(hybrid.XChaCha20Poly1305HKDFHybridPublicKeyTypeURL)
+	if err != nil {
+		t.Fatalf("registry.GetKeyManager(public) = _, %v; want _, nil", err)
+	}
+
+	m, err := privKM.NewKeyFromKeyFormat(&xcphpb.XChaCha20Poly1305HKDFHybridKeyFormat{
+		Params: &xcphpb.XChaCha20Poly1305HKDFHybridParams{ContextInfo: []byte("app-v1")},
+	})
+	if err != nil {
+		t.Fatalf("NewKeyFromKeyFormat() = _, %v; want _, nil", err)
+	}
+	privKey := m.(*xcphpb.XChaCha20Poly1305HKDFHybridPrivateKey)
+	serializedPriv, _ := proto.Marshal(privKey)
+
+	pubKeyData, err := privKM.PublicKeyData(serializedPriv)
+	if err != nil {
+		t.Fatalf("PublicKeyData() = _, %v; want _, nil", err)
+	}
+	if pubKeyData.TypeUrl != hybrid.XChaCha20Poly1305HKDFHybridPublicKeyTypeURL {
+		t.Errorf("PublicKeyData().TypeUrl = %s; want %s", pubKeyData.TypeUrl, hybrid.XChaCha20Poly1305HKDFHybridPublicKeyTypeURL)
+	}
+
+	encPrimitive, err := pubKM.GetPrimitiveFromSerializedKey(pubKeyData.Value)
+	if err != nil {
+		t.Fatalf("pubKM.GetPrimitiveFromSerializedKey() = _, %v; want _, nil", err)
+	}
+	decPrimitive, err := privKM.GetPrimitiveFromSerializedKey(serializedPriv)
+	if err != nil {
+		t.Fatalf("privKM.GetPrimitiveFromSerializedKey() = _, %v; want _, nil", err)
+	}
+	enc := encPrimitive.(*subtlehybrid.XChaCha20Poly1305HKDFHybridEncrypt)
+	dec := decPrimitive.(*subtlehybrid.XChaCha20Poly1305HKDFHybridDecrypt)
+
+	pt := random.GetRandomBytes(48)
+	ct, err := enc.Encrypt(pt, nil)
+	if err != nil {
+		t.Fatalf("Encrypt() = _, %v; want _, nil", err)
+	}
+	got, err := dec.Decrypt(ct, nil)
+	if err != nil {
+		t.Fatalf("Decrypt() = _, %v; want _, nil", err)
+	}
+	if !bytes.Equal(got, pt) {
+		t.Errorf("decrypted plaintext does not match original")
+	}
+}
+
+func TestXChaCha20Poly1305HKDFHybridPrivateKeyManagerRejectsInvalidKeys(t *testing.T) {
+	privKM := privateKeyManager(t)
+	invalidKeys := []*xcphpb.XChaCha20Poly1305HKDFHybridPrivateKey{
+		{
+			Version:    hybrid.XChaCha20Poly1305HKDFHybridPrivateKeyVersion + 1,
+			PrivateKey: random.GetRandomBytes(32),
+			PublicKey: &xcphpb.XChaCha20Poly1305HKDFHybridPublicKey{
+				PublicKey: random.GetRandomBytes(32),
+				Params:    &xcphpb.XChaCha20Poly1305HKDFHybridParams{},
+			},
+		},
+		{
+			Version:    hybrid.XChaCha20Poly1305HKDFHybridPrivateKeyVersion,
+			PrivateKey: random.GetRandomBytes(31),
+			PublicKey: &xcphpb.XChaCha20Poly1305HKDFHybridPublicKey{
+				PublicKey: random.GetRandomBytes(32),
+				Params:    &xcphpb.XChaCha20Poly1305HKDFHybridParams{},
+			},
+		},
+		{
+			Version:    hybrid.XChaCha20Poly1305HKDFHybridPrivateKeyVersion,
+			PrivateKey: random.GetRandomBytes(32),
+			PublicKey:  nil,
+		},
+	}
+	for _, key := range invalidKeys {
+		if _, err := privKM.GetPrimitiveFromKey(key); err == nil {
+			t.Errorf("GetPrimitiveFromKey(%v) = _, nil; want _, err", key)
+		}
+	}
+}
+
+func TestXChaCha20Poly1305HKDFHybridPublicKeyManagerDoesNotGenerateKeys(t *testing.T) {
+	km, err := registry.GetKeyManager(hybrid.XChaCha20Poly1305HKDFHybridPublicKeyTypeURL)
+	if err != nil {
+		t.Fatalf("registry.GetKeyManager(public) = _, %v; want _, nil", err)
+	}
+	if _, err := km.NewKeyData(nil); err == nil {
+		t.Errorf("public key manager's NewKeyData() = _, nil; want _, err")
+	}
+}
+
+func TestXChaCha20Poly1305HKDFHybridDoesSupport(t *testing.T) {
+	privKM := privateKeyManager(t)
+	if !privKM.DoesSupport(hybrid.XChaCha20Poly1305HKDFHybridPrivateKeyTypeURL) {
+		t.Errorf("must support %s", hybrid.XChaCha20Poly1305HKDFHybridPrivateKeyTypeURL)
+	}
+	if privKM.DoesSupport("some bad type") {
+		t.Errorf("must only support %s", hybrid.XChaCha20Poly1305HKDFHybridPrivateKeyTypeURL)
+	}
+}
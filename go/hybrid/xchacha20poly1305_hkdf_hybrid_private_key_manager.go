@@ -0,0 +1,185 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	subtlehybrid "github.com/google/tink/go/subtle/hybrid"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+	xcphpb "github.com/google/tink/proto/xchacha20_poly1305_hkdf_hybrid_go_proto"
+)
+
+const (
+	// XChaCha20Poly1305HKDFHybridPrivateKeyVersion is the maximum version of
+	// XChaCha20Poly1305HKDFHybridPrivateKey keys accepted by this key
+	// manager.
+	XChaCha20Poly1305HKDFHybridPrivateKeyVersion = 0
+	// XChaCha20Poly1305HKDFHybridPrivateKeyTypeURL is the type URL of
+	// XChaCha20Poly1305HKDFHybridPrivateKey keys that is supported by this
+	// key manager.
+	XChaCha20Poly1305HKDFHybridPrivateKeyTypeURL = "type.googleapis.com/google.crypto.tink.XChaCha20Poly1305HKDFHybridPrivateKey"
+
+	x25519PrivateKeySize = 32
+)
+
+var errInvalidXChaCha20Poly1305HKDFHybridPrivateKey = errors.New("xchacha20poly1305_hkdf_hybrid_private_key_manager: invalid key")
+var errInvalidXChaCha20Poly1305HKDFHybridKeyFormat = errors.New("xchacha20poly1305_hkdf_hybrid_private_key_manager: invalid key format")
+
+func init() {
+	if err := registry.RegisterKeyManager(new(xChaCha20Poly1305HKDFHybridPrivateKeyManager)); err != nil {
+		panic(fmt.Sprintf("hybrid.init() failed: %v", err))
+	}
+}
+
+// xChaCha20Poly1305HKDFHybridPrivateKeyManager generates
+// XChaCha20Poly1305HKDFHybridPrivateKey keys and produces instances of
+// XChaCha20Poly1305HKDFHybridDecrypt, which implements the HybridDecrypt
+// primitive.
+type xChaCha20Poly1305HKDFHybridPrivateKeyManager struct{}
+
+// GetPrimitiveFromSerializedKey creates a new
+// XChaCha20Poly1305HKDFHybridDecrypt from the given serialized
+// XChaCha20Poly1305HKDFHybridPrivateKey.
+func (km *xChaCha20Poly1305HKDFHybridPrivateKeyManager) GetPrimitiveFromSerializedKey(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidXChaCha20Poly1305HKDFHybridPrivateKey
+	}
+	key := new(xcphpb.XChaCha20Poly1305HKDFHybridPrivateKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidXChaCha20Poly1305HKDFHybridPrivateKey
+	}
+	return km.GetPrimitiveFromKey(key)
+}
+
+// GetPrimitiveFromKey creates a new XChaCha20Poly1305HKDFHybridDecrypt from
+// the given XChaCha20Poly1305HKDFHybridPrivateKey.
+func (km *xChaCha20Poly1305HKDFHybridPrivateKeyManager) GetPrimitiveFromKey(key *xcphpb.XChaCha20Poly1305HKDFHybridPrivateKey) (interface{}, error) {
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	return subtlehybrid.NewXChaCha20Poly1305HKDFHybridDecrypt(key.PrivateKey, key.PublicKey.PublicKey, key.PublicKey.Params.ContextInfo)
+}
+
+// NewKeyFromSerializedKeyFormat creates a new key according to the given
+// serialized XChaCha20Poly1305HKDFHybridKeyFormat.
+func (km *xChaCha20Poly1305HKDFHybridPrivateKeyManager) NewKeyFromSerializedKeyFormat(serializedKeyFormat []byte) (proto.Message, error) {
+	keyFormat := new(xcphpb.XChaCha20Poly1305HKDFHybridKeyFormat)
+	if len(serializedKeyFormat) > 0 {
+		if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+			return nil, errInvalidXChaCha20Poly1305HKDFHybridKeyFormat
+		}
+	}
+	return km.NewKeyFromKeyFormat(keyFormat)
+}
+
+// NewKeyFromKeyFormat creates a new key according to the given
+// XChaCha20Poly1305HKDFHybridKeyFormat. A nil keyFormat is treated as a
+// request for the default (empty context info) format.
+func (km *xChaCha20Poly1305HKDFHybridPrivateKeyManager) NewKeyFromKeyFormat(keyFormat proto.Message) (proto.Message, error) {
+	params := &xcphpb.XChaCha20Poly1305HKDFHybridParams{}
+	if keyFormat != nil {
+		format, ok := keyFormat.(*xcphpb.XChaCha20Poly1305HKDFHybridKeyFormat)
+		if !ok {
+			return nil, errInvalidXChaCha20Poly1305HKDFHybridKeyFormat
+		}
+		if format.Params != nil {
+			params = format.Params
+		}
+	}
+	priv, err := subtlehybrid.GeneratePrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("xchacha20poly1305_hkdf_hybrid_private_key_manager: %v", err)
+	}
+	pub, err := subtlehybrid.PublicFromPrivate(priv)
+	if err != nil {
+		return nil, fmt.Errorf("xchacha20poly1305_hkdf_hybrid_private_key_manager: %v", err)
+	}
+	return &xcphpb.XChaCha20Poly1305HKDFHybridPrivateKey{
+		Version:    XChaCha20Poly1305HKDFHybridPrivateKeyVersion,
+		PrivateKey: priv,
+		PublicKey: &xcphpb.XChaCha20Poly1305HKDFHybridPublicKey{
+			Version:   XChaCha20Poly1305HKDFHybridPublicKeyVersion,
+			PublicKey: pub,
+			Params:    params,
+		},
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to specification in the given
+// serialized XChaCha20Poly1305HKDFHybridKeyFormat.
+func (km *xChaCha20Poly1305HKDFHybridPrivateKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKeyFromSerializedKeyFormat(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, fmt.Errorf("xchacha20poly1305_hkdf_hybrid_private_key_manager: %v", err)
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         XChaCha20Poly1305HKDFHybridPrivateKeyTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+	}, nil
+}
+
+// PublicKeyData extracts the public key KeyData from the given serialized
+// XChaCha20Poly1305HKDFHybridPrivateKey, so that it can be shared with
+// senders independently of the private key.
+func (km *xChaCha20Poly1305HKDFHybridPrivateKeyManager) PublicKeyData(serializedKey []byte) (*tinkpb.KeyData, error) {
+	key := new(xcphpb.XChaCha20Poly1305HKDFHybridPrivateKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidXChaCha20Poly1305HKDFHybridPrivateKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	serializedPublicKey, err := proto.Marshal(key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("xchacha20poly1305_hkdf_hybrid_private_key_manager: %v", err)
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         XChaCha20Poly1305HKDFHybridPublicKeyTypeURL,
+		Value:           serializedPublicKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+	}, nil
+}
+
+// DoesSupport returns true iff this key manager supports key type typeURL.
+func (km *xChaCha20Poly1305HKDFHybridPrivateKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == XChaCha20Poly1305HKDFHybridPrivateKeyTypeURL
+}
+
+// GetKeyType returns the key type of keys managed by this key manager.
+func (km *xChaCha20Poly1305HKDFHybridPrivateKeyManager) GetKeyType() string {
+	return XChaCha20Poly1305HKDFHybridPrivateKeyTypeURL
+}
+
+func (km *xChaCha20Poly1305HKDFHybridPrivateKeyManager) validateKey(key *xcphpb.XChaCha20Poly1305HKDFHybridPrivateKey) error {
+	if key.Version != XChaCha20Poly1305HKDFHybridPrivateKeyVersion {
+		return errInvalidXChaCha20Poly1305HKDFHybridPrivateKey
+	}
+	if len(key.PrivateKey) != x25519PrivateKeySize {
+		return errInvalidXChaCha20Poly1305HKDFHybridPrivateKey
+	}
+	if key.PublicKey == nil || len(key.PublicKey.PublicKey) != x25519PublicKeySize || key.PublicKey.Params == nil {
+		return errInvalidXChaCha20Poly1305HKDFHybridPrivateKey
+	}
+	return nil
+}
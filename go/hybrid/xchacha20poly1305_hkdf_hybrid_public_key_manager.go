@@ -0,0 +1,118 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package hybrid provides implementations of the HybridEncrypt and
+// HybridDecrypt primitives.
+package hybrid
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	subtlehybrid "github.com/google/tink/go/subtle/hybrid"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+	xcphpb "github.com/google/tink/proto/xchacha20_poly1305_hkdf_hybrid_go_proto"
+)
+
+const (
+	// XChaCha20Poly1305HKDFHybridPublicKeyVersion is the maximum version of
+	// XChaCha20Poly1305HKDFHybridPublicKey keys accepted by this key
+	// manager.
+	XChaCha20Poly1305HKDFHybridPublicKeyVersion = 0
+	// XChaCha20Poly1305HKDFHybridPublicKeyTypeURL is the type URL of
+	// XChaCha20Poly1305HKDFHybridPublicKey keys that is supported by this
+	// key manager.
+	XChaCha20Poly1305HKDFHybridPublicKeyTypeURL = "type.googleapis.com/google.crypto.tink.XChaCha20Poly1305HKDFHybridPublicKey"
+
+	x25519PublicKeySize = 32
+)
+
+var errInvalidXChaCha20Poly1305HKDFHybridPublicKey = errors.New("xchacha20poly1305_hkdf_hybrid_public_key_manager: invalid key")
+
+func init() {
+	if err := registry.RegisterKeyManager(new(xChaCha20Poly1305HKDFHybridPublicKeyManager)); err != nil {
+		panic(fmt.Sprintf("hybrid.init() failed: %v", err))
+	}
+}
+
+// xChaCha20Poly1305HKDFHybridPublicKeyManager produces instances of
+// XChaCha20Poly1305HKDFHybridEncrypt, which implements the HybridEncrypt
+// primitive.
+type xChaCha20Poly1305HKDFHybridPublicKeyManager struct{}
+
+// GetPrimitiveFromSerializedKey creates a new
+// XChaCha20Poly1305HKDFHybridEncrypt from the given serialized
+// XChaCha20Poly1305HKDFHybridPublicKey.
+func (km *xChaCha20Poly1305HKDFHybridPublicKeyManager) GetPrimitiveFromSerializedKey(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidXChaCha20Poly1305HKDFHybridPublicKey
+	}
+	key := new(xcphpb.XChaCha20Poly1305HKDFHybridPublicKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidXChaCha20Poly1305HKDFHybridPublicKey
+	}
+	return km.GetPrimitiveFromKey(key)
+}
+
+// GetPrimitiveFromKey creates a new XChaCha20Poly1305HKDFHybridEncrypt from
+// the given XChaCha20Poly1305HKDFHybridPublicKey.
+func (km *xChaCha20Poly1305HKDFHybridPublicKeyManager) GetPrimitiveFromKey(key *xcphpb.XChaCha20Poly1305HKDFHybridPublicKey) (interface{}, error) {
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	return subtlehybrid.NewXChaCha20Poly1305HKDFHybridEncrypt(key.PublicKey, key.Params.ContextInfo)
+}
+
+// NewKeyFromSerializedKeyFormat is not supported: public keys are only ever
+// derived from a private key, never generated standalone.
+func (km *xChaCha20Poly1305HKDFHybridPublicKeyManager) NewKeyFromSerializedKeyFormat(serializedKeyFormat []byte) (proto.Message, error) {
+	return nil, errors.New("xchacha20poly1305_hkdf_hybrid_public_key_manager: public keys are not generated directly")
+}
+
+// NewKeyFromKeyFormat is not supported: public keys are only ever derived
+// from a private key, never generated standalone.
+func (km *xChaCha20Poly1305HKDFHybridPublicKeyManager) NewKeyFromKeyFormat(keyFormat proto.Message) (proto.Message, error) {
+	return nil, errors.New("xchacha20poly1305_hkdf_hybrid_public_key_manager: public keys are not generated directly")
+}
+
+// NewKeyData is not supported: public keys are only ever derived from a
+// private key, never generated standalone.
+func (km *xChaCha20Poly1305HKDFHybridPublicKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	return nil, errors.New("xchacha20poly1305_hkdf_hybrid_public_key_manager: public keys are not generated directly")
+}
+
+// DoesSupport returns true iff this key manager supports key type typeURL.
+func (km *xChaCha20Poly1305HKDFHybridPublicKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == XChaCha20Poly1305HKDFHybridPublicKeyTypeURL
+}
+
+// GetKeyType returns the key type of keys managed by this key manager.
+func (km *xChaCha20Poly1305HKDFHybridPublicKeyManager) GetKeyType() string {
+	return XChaCha20Poly1305HKDFHybridPublicKeyTypeURL
+}
+
+func (km *xChaCha20Poly1305HKDFHybridPublicKeyManager) validateKey(key *xcphpb.XChaCha20Poly1305HKDFHybridPublicKey) error {
+	if key.Version != XChaCha20Poly1305HKDFHybridPublicKeyVersion {
+		return errInvalidXChaCha20Poly1305HKDFHybridPublicKey
+	}
+	if len(key.PublicKey) != x25519PublicKeySize {
+		return errInvalidXChaCha20Poly1305HKDFHybridPublicKey
+	}
+	if key.Params == nil {
+		return errInvalidXChaCha20Poly1305HKDFHybridPublicKey
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package streamingaead provides implementations of the StreamingAEAD
+// primitive.
+//
+// StreamingAEAD encrypts data in a streaming fashion: instead of taking the
+// whole plaintext in memory at once, it processes it in segments, which
+// makes it suitable for large files that should not be buffered whole.
+package streamingaead
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/subtle/random"
+	subtlesaead "github.com/google/tink/go/subtle/streamingaead"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+	xcsspb "github.com/google/tink/proto/xchacha20_poly1305_hkdf_streaming_go_proto"
+)
+
+const (
+	// XChaCha20Poly1305HKDFStreamingKeyVersion is the maximum version of
+	// XChaCha20Poly1305HKDFStreamingKey keys accepted by this key manager.
+	XChaCha20Poly1305HKDFStreamingKeyVersion = 0
+	// XChaCha20Poly1305HKDFStreamingTypeURL is the type URL of
+	// XChaCha20Poly1305HKDFStreamingKey keys that is supported by this key
+	// manager.
+	XChaCha20Poly1305HKDFStreamingTypeURL = "type.googleapis.com/google.crypto.tink.XChaCha20Poly1305HKDFStreamingKey"
+
+	xChaCha20Poly1305HKDFStreamingKeySize = 32
+)
+
+var errInvalidXChaCha20Poly1305HKDFStreamingKey = errors.New("xchacha20poly1305_hkdf_streaming_key_manager: invalid key")
+var errInvalidXChaCha20Poly1305HKDFStreamingKeyFormat = errors.New("xchacha20poly1305_hkdf_streaming_key_manager: invalid key format")
+
+func init() {
+	if err := registry.RegisterKeyManager(NewXChaCha20Poly1305HKDFStreamingKeyManager()); err != nil {
+		panic(fmt.Sprintf("streamingaead.init() failed: %v", err))
+	}
+}
+
+// XChaCha20Poly1305HKDFStreamingKeyManager generates
+// XChaCha20Poly1305HKDFStreamingKey keys and produces instances of
+// XChaCha20Poly1305HKDFStream, which implements the StreamingAEAD primitive.
+type XChaCha20Poly1305HKDFStreamingKeyManager struct{}
+
+// NewXChaCha20Poly1305HKDFStreamingKeyManager creates a new
+// XChaCha20Poly1305HKDFStreamingKeyManager.
+func NewXChaCha20Poly1305HKDFStreamingKeyManager() *XChaCha20Poly1305HKDFStreamingKeyManager {
+	return new(XChaCha20Poly1305HKDFStreamingKeyManager)
+}
+
+// GetPrimitiveFromSerializedKey creates a new XChaCha20Poly1305HKDFStream
+// from the given serialized XChaCha20Poly1305HKDFStreamingKey.
+func (km *XChaCha20Poly1305HKDFStreamingKeyManager) GetPrimitiveFromSerializedKey(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidXChaCha20Poly1305HKDFStreamingKey
+	}
+	key := new(xcsspb.XChaCha20Poly1305HKDFStreamingKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidXChaCha20Poly1305HKDFStreamingKey
+	}
+	return km.GetPrimitiveFromKey(key)
+}
+
+// GetPrimitiveFromKey creates a new XChaCha20Poly1305HKDFStream from the
+// given XChaCha20Poly1305HKDFStreamingKey.
+func (km *XChaCha20Poly1305HKDFStreamingKeyManager) GetPrimitiveFromKey(key *xcsspb.XChaCha20Poly1305HKDFStreamingKey) (interface{}, error) {
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	return subtlesaead.NewXChaCha20Poly1305HKDFStream(key.KeyValue, int(key.Params.CiphertextSegmentSize))
+}
+
+// NewKeyFromSerializedKeyFormat creates a new key according to the given
+// serialized XChaCha20Poly1305HKDFStreamingKeyFormat.
+func (km *XChaCha20Poly1305HKDFStreamingKeyManager) NewKeyFromSerializedKeyFormat(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidXChaCha20Poly1305HKDFStreamingKeyFormat
+	}
+	keyFormat := new(xcsspb.XChaCha20Poly1305HKDFStreamingKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidXChaCha20Poly1305HKDFStreamingKeyFormat
+	}
+	return km.NewKeyFromKeyFormat(keyFormat)
+}
+
+// NewKeyFromKeyFormat creates a new key according to the given
+// XChaCha20Poly1305HKDFStreamingKeyFormat.
+func (km *XChaCha20Poly1305HKDFStreamingKeyManager) NewKeyFromKeyFormat(keyFormat proto.Message) (proto.Message, error) {
+	format, ok := keyFormat.(*xcsspb.XChaCha20Poly1305HKDFStreamingKeyFormat)
+	if !ok {
+		return nil, errInvalidXChaCha20Poly1305HKDFStreamingKeyFormat
+	}
+	if err := km.validateKeyFormat(format); err != nil {
+		return nil, err
+	}
+	return &xcsspb.XChaCha20Poly1305HKDFStreamingKey{
+		Version:  XChaCha20Poly1305HKDFStreamingKeyVersion,
+		KeyValue: random.GetRandomBytes(xChaCha20Poly1305HKDFStreamingKeySize),
+		Params:   format.Params,
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to specification in the given
+// serialized XChaCha20Poly1305HKDFStreamingKeyFormat.
+func (km *XChaCha20Poly1305HKDFStreamingKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKeyFromSerializedKeyFormat(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, fmt.Errorf("xchacha20poly1305_hkdf_streaming_key_manager: %v", err)
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         XChaCha20Poly1305HKDFStreamingTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport returns true iff this key manager supports key type typeURL.
+func (km *XChaCha20Poly1305HKDFStreamingKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == XChaCha20Poly1305HKDFStreamingTypeURL
+}
+
+// GetKeyType returns the key type of keys managed by this key manager.
+func (km *XChaCha20Poly1305HKDFStreamingKeyManager) GetKeyType() string {
+	return XChaCha20Poly1305HKDFStreamingTypeURL
+}
+
+func (km *XChaCha20Poly1305HKDFStreamingKeyManager) validateKey(key *xcsspb.XChaCha20Poly1305HKDFStreamingKey) error {
+	if key.Version != XChaCha20Poly1305HKDFStreamingKeyVersion {
+		return errInvalidXChaCha20Poly1305HKDFStreamingKey
+	}
+	if len(key.KeyValue) != xChaCha20Poly1305HKDFStreamingKeySize {
+		return errInvalidXChaCha20Poly1305HKDFStreamingKey
+	}
+	if key.Params == nil || key.Params.CiphertextSegmentSize < 1024 {
+		return errInvalidXChaCha20Poly1305HKDFStreamingKey
+	}
+	return nil
+}
+
+func (km *XChaCha20Poly1305HKDFStreamingKeyManager) validateKeyFormat(format *xcsspb.XChaCha20Poly1305HKDFStreamingKeyFormat) error {
+	if format.KeySize != xChaCha20Poly1305HKDFStreamingKeySize {
+		return errInvalidXChaCha20Poly1305HKDFStreamingKeyFormat
+	}
+	if format.Params == nil || format.Params.CiphertextSegmentSize < 1024 {
+		return errInvalidXChaCha20Poly1305HKDFStreamingKeyFormat
+	}
+	return nil
+}
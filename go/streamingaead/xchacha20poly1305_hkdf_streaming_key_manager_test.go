@@ -0,0 +1,136 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/streamingaead"
+	"github.com/google/tink/go/subtle/random"
+
+	subtlesaead "github.com/google/tink/go/subtle/streamingaead"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+	xcsspb "github.com/google/tink/proto/xchacha20_poly1305_hkdf_streaming_go_proto"
+)
+
+func testKeyFormat() *xcsspb.XChaCha20Poly1305HKDFStreamingKeyFormat {
+	return &xcsspb.XChaCha20Poly1305HKDFStreamingKeyFormat{
+		KeySize: 32,
+		Params:  &xcsspb.XChaCha20Poly1305HKDFStreamingParams{CiphertextSegmentSize: 4096},
+	}
+}
+
+func TestNewXChaCha20Poly1305HKDFStreamingKeyManager(t *testing.T) {
+	km := streamingaead.NewXChaCha20Poly1305HKDFStreamingKeyManager()
+	if km == nil {
+		t.Errorf("NewXChaCha20Poly1305HKDFStreamingKeyManager() returns nil")
+	}
+}
+
+func TestXChaCha20Poly1305HKDFStreamingGetPrimitive(t *testing.T) {
+	km := streamingaead.NewXChaCha20Poly1305HKDFStreamingKeyManager()
+	serializedFormat, _ := proto.Marshal(testKeyFormat())
+	m, err := km.NewKeyFromSerializedKeyFormat(serializedFormat)
+	if err != nil {
+		t.Fatalf("km.NewKeyFromSerializedKeyFormat() = _, %v; want _, nil", err)
+	}
+	key := m.(*xcsspb.XChaCha20Poly1305HKDFStreamingKey)
+
+	p, err := km.GetPrimitiveFromKey(key)
+	if err != nil {
+		t.Fatalf("km.GetPrimitiveFromKey(%v) = _, %v; want _, nil", key, err)
+	}
+	stream := p.(*subtlesaead.XChaCha20Poly1305HKDFStream)
+
+	pt := random.GetRandomBytes(8192)
+	aad := random.GetRandomBytes(16)
+	var buf bytes.Buffer
+	w, err := stream.NewEncryptingWriter(&buf, aad)
+	if err != nil {
+		t.Fatalf("NewEncryptingWriter() = _, %v; want _, nil", err)
+	}
+	if _, err := w.Write(pt); err != nil {
+		t.Fatalf("w.Write() = _, %v; want _, nil", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("w.Close() = %v; want nil", err)
+	}
+
+	r, err := stream.NewDecryptingReader(bytes.NewReader(buf.Bytes()), aad)
+	if err != nil {
+		t.Fatalf("NewDecryptingReader() = _, %v; want _, nil", err)
+	}
+	got, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll() = _, %v; want _, nil", err)
+	}
+	if !bytes.Equal(got, pt) {
+		t.Errorf("decrypted plaintext does not match original")
+	}
+}
+
+func TestXChaCha20Poly1305HKDFStreamingGetPrimitiveWithInvalidKeys(t *testing.T) {
+	km := streamingaead.NewXChaCha20Poly1305HKDFStreamingKeyManager()
+	invalidKeys := []*xcsspb.XChaCha20Poly1305HKDFStreamingKey{
+		{
+			Version:  streamingaead.XChaCha20Poly1305HKDFStreamingKeyVersion,
+			KeyValue: random.GetRandomBytes(16),
+			Params:   &xcsspb.XChaCha20Poly1305HKDFStreamingParams{CiphertextSegmentSize: 4096},
+		},
+		{
+			Version:  streamingaead.XChaCha20Poly1305HKDFStreamingKeyVersion,
+			KeyValue: random.GetRandomBytes(32),
+			Params:   &xcsspb.XChaCha20Poly1305HKDFStreamingParams{CiphertextSegmentSize: 10},
+		},
+		{
+			Version:  streamingaead.XChaCha20Poly1305HKDFStreamingKeyVersion + 1,
+			KeyValue: random.GetRandomBytes(32),
+			Params:   &xcsspb.XChaCha20Poly1305HKDFStreamingParams{CiphertextSegmentSize: 4096},
+		},
+	}
+	for _, key := range invalidKeys {
+		if _, err := km.GetPrimitiveFromKey(key); err == nil {
+			t.Errorf("km.GetPrimitiveFromKey(%v) = _, nil; want _, err", key)
+		}
+	}
+}
+
+func TestXChaCha20Poly1305HKDFStreamingNewKeyData(t *testing.T) {
+	km := streamingaead.NewXChaCha20Poly1305HKDFStreamingKeyManager()
+	serializedFormat, _ := proto.Marshal(testKeyFormat())
+	kd, err := km.NewKeyData(serializedFormat)
+	if err != nil {
+		t.Fatalf("km.NewKeyData() = _, %v; want _, nil", err)
+	}
+	if kd.TypeUrl != streamingaead.XChaCha20Poly1305HKDFStreamingTypeURL {
+		t.Errorf("TypeUrl: %v != %v", kd.TypeUrl, streamingaead.XChaCha20Poly1305HKDFStreamingTypeURL)
+	}
+	if kd.KeyMaterialType != tinkpb.KeyData_SYMMETRIC {
+		t.Errorf("KeyMaterialType: %v != SYMMETRIC", kd.KeyMaterialType)
+	}
+}
+
+func TestXChaCha20Poly1305HKDFStreamingDoesSupport(t *testing.T) {
+	km := streamingaead.NewXChaCha20Poly1305HKDFStreamingKeyManager()
+	if !km.DoesSupport(streamingaead.XChaCha20Poly1305HKDFStreamingTypeURL) {
+		t.Errorf("must support %s", streamingaead.XChaCha20Poly1305HKDFStreamingTypeURL)
+	}
+	if km.DoesSupport("some bad type") {
+		t.Errorf("must only support %s", streamingaead.XChaCha20Poly1305HKDFStreamingTypeURL)
+	}
+}
@@ -0,0 +1,277 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/subtle/random"
+)
+
+const (
+	// AESGCMSIVNonceSize is the only supported nonce size, in bytes.
+	AESGCMSIVNonceSize = 12
+	// AESGCMSIVTagSize is the size of the authentication tag, in bytes.
+	AESGCMSIVTagSize = 16
+)
+
+// AESGCMSIV is an implementation of AEAD interface.
+//
+// Unlike AESGCM, AESGCMSIV is a nonce-misuse-resistant construction (RFC
+// 8452): repeating a nonce only leaks whether two (plaintext,
+// additionalData) pairs were equal, and it never breaks confidentiality the
+// way a nonce reuse does for ordinary AES-GCM. This
+// makes it a safer default when nonces are derived rather than drawn from
+// a CSPRNG, e.g. in streaming or replay-prone contexts.
+type AESGCMSIV struct {
+	Key []byte
+}
+
+// NewAESGCMSIV returns an AESGCMSIV instance.
+// The key argument should be the AES key, either 16 or 32 bytes to select
+// AES-128-GCM-SIV or AES-256-GCM-SIV, respectively.
+func NewAESGCMSIV(key []byte) (*AESGCMSIV, error) {
+	keySize := len(key)
+	if keySize != 16 && keySize != 32 {
+		return nil, fmt.Errorf("aes_gcm_siv: invalid AES key size; want 16 or 32, got %d", keySize)
+	}
+	keyCopy := make([]byte, keySize)
+	copy(keyCopy, key)
+	return &AESGCMSIV{Key: keyCopy}, nil
+}
+
+// Encrypt encrypts plaintext with additionalData. The resulting ciphertext
+// consists of (nonce || ciphertext || tag).
+func (a *AESGCMSIV) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	nonce := random.GetRandomBytes(AESGCMSIVNonceSize)
+	ct, tag, err := a.seal(nonce, plaintext, additionalData)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext := make([]byte, 0, len(nonce)+len(ct)+len(tag))
+	ciphertext = append(ciphertext, nonce...)
+	ciphertext = append(ciphertext, ct...)
+	ciphertext = append(ciphertext, tag...)
+	return ciphertext, nil
+}
+
+// Decrypt decrypts ciphertext with additionalData.
+func (a *AESGCMSIV) Decrypt(ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < AESGCMSIVNonceSize+AESGCMSIVTagSize {
+		return nil, errors.New("aes_gcm_siv: ciphertext too short")
+	}
+	nonce := ciphertext[:AESGCMSIVNonceSize]
+	ct := ciphertext[AESGCMSIVNonceSize : len(ciphertext)-AESGCMSIVTagSize]
+	tag := ciphertext[len(ciphertext)-AESGCMSIVTagSize:]
+	return a.open(nonce, ct, tag, additionalData)
+}
+
+func (a *AESGCMSIV) seal(nonce, plaintext, additionalData []byte) (ct, tag []byte, err error) {
+	if len(nonce) != AESGCMSIVNonceSize {
+		return nil, nil, fmt.Errorf("aes_gcm_siv: invalid nonce size; want %d, got %d", AESGCMSIVNonceSize, len(nonce))
+	}
+	block, err := aes.NewCipher(a.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	macKey, encKey := deriveKeys(block, nonce, len(a.Key))
+
+	s := polyvalInputSum(macKey, additionalData, plaintext)
+	tagBytes := computeTag(s, nonce, encKey)
+
+	encBlock, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctrBlock := tagBytes
+	ctrBlock[15] |= 0x80
+	ct = ctrXOR(encBlock, ctrBlock, plaintext)
+	return ct, tagBytes[:], nil
+}
+
+func (a *AESGCMSIV) open(nonce, ct, tag, additionalData []byte) ([]byte, error) {
+	if len(nonce) != AESGCMSIVNonceSize {
+		return nil, fmt.Errorf("aes_gcm_siv: invalid nonce size; want %d, got %d", AESGCMSIVNonceSize, len(nonce))
+	}
+	if len(tag) != AESGCMSIVTagSize {
+		return nil, fmt.Errorf("aes_gcm_siv: invalid tag size; want %d, got %d", AESGCMSIVTagSize, len(tag))
+	}
+	block, err := aes.NewCipher(a.Key)
+	if err != nil {
+		return nil, err
+	}
+	macKey, encKey := deriveKeys(block, nonce, len(a.Key))
+
+	encBlock, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+	var ctrBlock [16]byte
+	copy(ctrBlock[:], tag)
+	ctrBlock[15] |= 0x80
+	plaintext := ctrXOR(encBlock, ctrBlock, ct)
+
+	s := polyvalInputSum(macKey, additionalData, plaintext)
+	wantTag := computeTag(s, nonce, encKey)
+	if subtle.ConstantTimeCompare(wantTag[:], tag) != 1 {
+		return nil, errors.New("aes_gcm_siv: authentication failed")
+	}
+	return plaintext, nil
+}
+
+// computeTag derives the 16-byte authentication tag (which doubles as the
+// initial CTR counter block) from the POLYVAL sum S_s, the nonce and the
+// record-encryption key, as specified in RFC 8452 Section 4.
+func computeTag(s [16]byte, nonce []byte, encKey []byte) [16]byte {
+	for i := 0; i < AESGCMSIVNonceSize; i++ {
+		s[i] ^= nonce[i]
+	}
+	s[15] &= 0x7f
+
+	encBlock, err := aes.NewCipher(encKey)
+	if err != nil {
+		// encKey size was already validated by the caller's call to
+		// deriveKeys, so aes.NewCipher cannot fail here.
+		panic(err)
+	}
+	var tag [16]byte
+	encBlock.Encrypt(tag[:], s[:])
+	return tag
+}
+
+// polyvalInputSum computes POLYVAL(macKey, pad16(aad), pad16(pt), lengthBlock).
+func polyvalInputSum(macKey []byte, aad, pt []byte) [16]byte {
+	lengthBlock := make([]byte, 16)
+	binary.LittleEndian.PutUint64(lengthBlock[0:8], uint64(len(aad))*8)
+	binary.LittleEndian.PutUint64(lengthBlock[8:16], uint64(len(pt))*8)
+
+	input := make([]byte, 0, pad16Len(len(aad))+pad16Len(len(pt))+16)
+	input = append(input, aad...)
+	input = append(input, make([]byte, pad16Len(len(aad))-len(aad))...)
+	input = append(input, pt...)
+	input = append(input, make([]byte, pad16Len(len(pt))-len(pt))...)
+	input = append(input, lengthBlock...)
+
+	var h [16]byte
+	copy(h[:], macKey)
+	return polyval(h, input)
+}
+
+func pad16Len(n int) int {
+	if n%16 == 0 {
+		return n
+	}
+	return n + (16 - n%16)
+}
+
+// deriveKeys implements the key derivation of RFC 8452 Section 4: it derives
+// a 16-byte record-authentication key and a record-encryption key (the same
+// size as masterKeySize) from the AES-GCM-SIV key and the message nonce.
+func deriveKeys(block cipher.Block, nonce []byte, masterKeySize int) (macKey, encKey []byte) {
+	numBlocks := 4
+	if masterKeySize == 32 {
+		numBlocks = 6
+	}
+	derived := make([]byte, 0, numBlocks*8)
+	var in, out [16]byte
+	copy(in[4:16], nonce)
+	for i := 0; i < numBlocks; i++ {
+		binary.LittleEndian.PutUint32(in[0:4], uint32(i))
+		block.Encrypt(out[:], in[:])
+		derived = append(derived, out[:8]...)
+	}
+	return derived[0:16], derived[16:]
+}
+
+// ctrXOR encrypts/decrypts src with AES-CTR, where only the low 32 bits of
+// the counter block (little-endian) are incremented and the remaining 96
+// bits stay fixed, as required by RFC 8452 (as opposed to the big-endian,
+// whole-block counter used by crypto/cipher.NewCTR).
+func ctrXOR(block cipher.Block, initialCounterBlock [16]byte, src []byte) []byte {
+	dst := make([]byte, len(src))
+	counterBlock := initialCounterBlock
+	ctr := binary.LittleEndian.Uint32(counterBlock[0:4])
+	var ks [16]byte
+	for i := 0; i < len(src); i += 16 {
+		binary.LittleEndian.PutUint32(counterBlock[0:4], ctr)
+		block.Encrypt(ks[:], counterBlock[:])
+		end := i + 16
+		if end > len(src) {
+			end = len(src)
+		}
+		for j := i; j < end; j++ {
+			dst[j] = src[j] ^ ks[j-i]
+		}
+		ctr++
+	}
+	return dst
+}
+
+// xInv128 is x^-128 mod (x^128 + x^127 + x^126 + x^121 + 1), in the same
+// bit convention as gfMul. RFC 8452's dot operation (the multiplication used
+// by POLYVAL) is defined as ordinary GF(2^128) multiplication followed by an
+// implicit division by x^128; folding that factor into h once per call, via
+// this constant, lets the per-block accumulation use plain gfMul.
+var xInv128 = [16]byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x04, 0x92}
+
+// polyval evaluates POLYVAL(h, data) as defined in RFC 8452 Section 3, where
+// data is the concatenation of 16-byte blocks (the caller is responsible for
+// zero-padding each logical field to a block boundary).
+func polyval(h [16]byte, data []byte) [16]byte {
+	hAdj := gfMul(h, xInv128)
+	var acc [16]byte
+	for i := 0; i < len(data); i += 16 {
+		var block [16]byte
+		copy(block[:], data[i:])
+		for j := range acc {
+			acc[j] ^= block[j]
+		}
+		acc = gfMul(acc, hAdj)
+	}
+	return acc
+}
+
+// gfMul multiplies a and b as ordinary polynomials in the field GF(2^128),
+// reduced modulo x^128 + x^127 + x^126 + x^121 + 1. Bit i of byte i/8 is the
+// coefficient of x^i (i.e. byte 0 holds the low-order coefficients), which
+// is the opposite bit order from GHASH. Note that this is plain polynomial
+// multiplication, not RFC 8452's "dot" operation used by POLYVAL directly;
+// see xInv128 and polyval above for the adjustment POLYVAL requires.
+func gfMul(a, b [16]byte) [16]byte {
+	var z [16]byte
+	v := b
+	for i := 0; i < 128; i++ {
+		if (a[i/8]>>(uint(i)%8))&1 == 1 {
+			for j := range z {
+				z[j] ^= v[j]
+			}
+		}
+		carry := v[15] >> 7
+		for j := 15; j > 0; j-- {
+			v[j] = (v[j] << 1) | (v[j-1] >> 7)
+		}
+		v[0] <<= 1
+		if carry == 1 {
+			v[15] ^= 0xc2
+			v[0] ^= 0x01
+		}
+	}
+	return z
+}
@@ -0,0 +1,182 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// This file is an internal (white-box) test: it exercises seal/open
+// directly with an explicit nonce, which the public Encrypt/Decrypt API
+// deliberately does not expose. That access is needed both to check
+// against fixed RFC 8452 test vectors and to force two encryptions to
+// share a nonce, which random nonce generation can't reliably reproduce.
+package aead
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// aesGCMSIVVector is a known-answer test case from RFC 8452 Appendix C.
+type aesGCMSIVVector struct {
+	name       string
+	key        string
+	nonce      string
+	aad        string
+	plaintext  string
+	ciphertext string // result, i.e. ciphertext || tag
+}
+
+// aesGCMSIVVectors are taken verbatim from RFC 8452 Appendix C.1 (AES-128)
+// and C.2 (AES-256).
+var aesGCMSIVVectors = []aesGCMSIVVector{
+	{
+		name:       "AES-128-GCM-SIV, empty plaintext and AAD",
+		key:        "01000000000000000000000000000000",
+		nonce:      "030000000000000000000000",
+		aad:        "",
+		plaintext:  "",
+		ciphertext: "dc20e2d83f25705bb49e439eca56de25",
+	},
+	{
+		name:       "AES-128-GCM-SIV, 8-byte plaintext",
+		key:        "01000000000000000000000000000000",
+		nonce:      "030000000000000000000000",
+		aad:        "",
+		plaintext:  "0100000000000000",
+		ciphertext: "b5d839330ac7b786578782fff6013b815b287c22493a364c",
+	},
+	{
+		name:       "AES-128-GCM-SIV, 8-byte plaintext with 1-byte AAD",
+		key:        "01000000000000000000000000000000",
+		nonce:      "030000000000000000000000",
+		aad:        "01",
+		plaintext:  "0200000000000000",
+		ciphertext: "1e6daba35669f4273b0a1a2560969cdf790d99759abd1508",
+	},
+	{
+		name:       "AES-256-GCM-SIV, empty plaintext and AAD",
+		key:        "0100000000000000000000000000000000000000000000000000000000000000",
+		nonce:      "030000000000000000000000",
+		aad:        "",
+		plaintext:  "",
+		ciphertext: "07f5f4169bbf55a8400cd47ea6fd400f",
+	},
+	{
+		name:       "AES-256-GCM-SIV, 8-byte plaintext",
+		key:        "0100000000000000000000000000000000000000000000000000000000000000",
+		nonce:      "030000000000000000000000",
+		aad:        "",
+		plaintext:  "0100000000000000",
+		ciphertext: "c2ef328e5c71c83b843122130f7364b761e0b97427e3df28",
+	},
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("hex.DecodeString(%q) = _, %v; want _, nil", s, err)
+	}
+	return b
+}
+
+func TestAESGCMSIVRFC8452Vectors(t *testing.T) {
+	for _, v := range aesGCMSIVVectors {
+		t.Run(v.name, func(t *testing.T) {
+			a := &AESGCMSIV{Key: mustHex(t, v.key)}
+			nonce := mustHex(t, v.nonce)
+			aad := mustHex(t, v.aad)
+			pt := mustHex(t, v.plaintext)
+			want := mustHex(t, v.ciphertext)
+
+			ct, tag, err := a.seal(nonce, pt, aad)
+			if err != nil {
+				t.Fatalf("seal() = _, _, %v; want _, _, nil", err)
+			}
+			got := append(append([]byte{}, ct...), tag...)
+			if !bytes.Equal(got, want) {
+				t.Errorf("seal() = %x; want %x", got, want)
+			}
+
+			gotPt, err := a.open(nonce, ct, tag, aad)
+			if err != nil {
+				t.Fatalf("open() = _, %v; want _, nil", err)
+			}
+			if !bytes.Equal(gotPt, pt) {
+				t.Errorf("open() = %x; want %x", gotPt, pt)
+			}
+		})
+	}
+}
+
+// TestAESGCMSIVSameNonceIsDeterministic checks that encrypting the same
+// (key, nonce, plaintext, aad) twice yields byte-identical output, as the
+// SIV construction requires.
+func TestAESGCMSIVSameNonceIsDeterministic(t *testing.T) {
+	a := &AESGCMSIV{Key: mustHex(t, "0100000000000000000000000000000000000000000000000000000000000000")}
+	nonce := mustHex(t, "030000000000000000000000")
+	pt := mustHex(t, "0100000000000000")
+
+	ct1, tag1, err := a.seal(nonce, pt, nil)
+	if err != nil {
+		t.Fatalf("seal() = _, _, %v; want _, _, nil", err)
+	}
+	ct2, tag2, err := a.seal(nonce, pt, nil)
+	if err != nil {
+		t.Fatalf("seal() = _, _, %v; want _, _, nil", err)
+	}
+	if !bytes.Equal(ct1, ct2) || !bytes.Equal(tag1, tag2) {
+		t.Errorf("two seal() calls with the same (key, nonce, plaintext, aad) produced different output")
+	}
+}
+
+// TestAESGCMSIVSameNonceDifferentMessagesDoesNotLeakXOR demonstrates the
+// property that makes AES-GCM-SIV nonce-misuse resistant: under ordinary
+// AES-CTR-based AEADs (including AES-GCM), reusing a nonce for two
+// messages leaks plaintext1 XOR plaintext2 from ciphertext1 XOR
+// ciphertext2, because both messages are encrypted with the same
+// keystream. AES-GCM-SIV derives its counter block from a POLYVAL MAC
+// over the message, so two different messages under the same nonce use
+// different keystreams and this XOR relationship does not hold.
+func TestAESGCMSIVSameNonceDifferentMessagesDoesNotLeakXOR(t *testing.T) {
+	a := &AESGCMSIV{Key: mustHex(t, "0100000000000000000000000000000000000000000000000000000000000000")}
+	nonce := mustHex(t, "030000000000000000000000")
+	aad := mustHex(t, "01")
+	pt1 := mustHex(t, "0100000000000000")
+	pt2 := mustHex(t, "0200000000000000")
+
+	ct1, tag1, err := a.seal(nonce, pt1, aad)
+	if err != nil {
+		t.Fatalf("seal(pt1) = _, _, %v; want _, _, nil", err)
+	}
+	ct2, tag2, err := a.seal(nonce, pt2, aad)
+	if err != nil {
+		t.Fatalf("seal(pt2) = _, _, %v; want _, _, nil", err)
+	}
+	if bytes.Equal(tag1, tag2) {
+		t.Errorf("two different messages under the same nonce produced the same tag")
+	}
+
+	ctXOR := xorBytes(ct1, ct2)
+	ptXOR := xorBytes(pt1, pt2)
+	if bytes.Equal(ctXOR, ptXOR) {
+		t.Errorf("ciphertext XOR equals plaintext XOR under nonce reuse; AES-GCM-SIV should not exhibit this AES-GCM/CTR failure mode")
+	}
+}
+
+func xorBytes(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range out {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// derivedXChaCha20Poly1305KeySize is the size, in bytes, of the master key
+// and of every subkey derived from it.
+const derivedXChaCha20Poly1305KeySize = 32
+
+// DerivedXChaCha20Poly1305 is an AEAD that, instead of encrypting directly
+// under a single key, derives a fresh XChaCha20-Poly1305 subkey per logical
+// scope (per-user, per-file, per-tenant, ...) from one master key via
+// HKDF-SHA256. The master key is never used for encryption itself and never
+// needs to leave this primitive, so callers can safely register one key in
+// a keyset and serve many scopes from it.
+type DerivedXChaCha20Poly1305 struct {
+	MasterKey []byte
+	Info      []byte
+}
+
+// NewDerivedXChaCha20Poly1305 returns a DerivedXChaCha20Poly1305 primitive
+// backed by the given 32-byte master key. info is the default HKDF info
+// label; it is concatenated with the per-call context to form the actual
+// HKDF info parameter.
+func NewDerivedXChaCha20Poly1305(masterKey, info []byte) (*DerivedXChaCha20Poly1305, error) {
+	if len(masterKey) != derivedXChaCha20Poly1305KeySize {
+		return nil, fmt.Errorf("derived_xchacha20poly1305: invalid master key size; want %d, got %d", derivedXChaCha20Poly1305KeySize, len(masterKey))
+	}
+	return &DerivedXChaCha20Poly1305{MasterKey: masterKey, Info: info}, nil
+}
+
+// Encrypt derives a subkey bound to context and seals plaintext with it,
+// returning (nonce || ciphertext || tag) as produced by XChaCha20Poly1305.
+func (d *DerivedXChaCha20Poly1305) Encrypt(plaintext, additionalData []byte, context string) ([]byte, error) {
+	cipher, err := d.deriveCipher(context)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.Encrypt(plaintext, additionalData)
+}
+
+// Decrypt derives the subkey bound to context and opens ciphertext with it.
+// context must match the value passed to Encrypt.
+func (d *DerivedXChaCha20Poly1305) Decrypt(ciphertext, additionalData []byte, context string) ([]byte, error) {
+	cipher, err := d.deriveCipher(context)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.Decrypt(ciphertext, additionalData)
+}
+
+func (d *DerivedXChaCha20Poly1305) deriveCipher(context string) (*XChaCha20Poly1305, error) {
+	info := append(append([]byte{}, d.Info...), []byte(context)...)
+	reader := hkdf.New(sha256.New, d.MasterKey, nil, info)
+	subKey := make([]byte, derivedXChaCha20Poly1305KeySize)
+	if _, err := io.ReadFull(reader, subKey); err != nil {
+		return nil, fmt.Errorf("derived_xchacha20poly1305: failed to derive subkey: %v", err)
+	}
+	return NewXChaCha20Poly1305(subKey)
+}
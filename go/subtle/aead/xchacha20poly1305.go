@@ -0,0 +1,64 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/google/tink/go/subtle/random"
+)
+
+// XChaCha20Poly1305 is an implementation of AEAD interface.
+type XChaCha20Poly1305 struct {
+	Key []byte
+}
+
+// NewXChaCha20Poly1305 returns an XChaCha20Poly1305 instance.
+// The key argument should be a 32-byte key.
+func NewXChaCha20Poly1305(key []byte) (*XChaCha20Poly1305, error) {
+	keySize := len(key)
+	if keySize != chacha20poly1305.KeySize {
+		return nil, fmt.Errorf("xchacha20poly1305: invalid key size; want %d, got %d", chacha20poly1305.KeySize, keySize)
+	}
+	keyCopy := make([]byte, keySize)
+	copy(keyCopy, key)
+	return &XChaCha20Poly1305{Key: keyCopy}, nil
+}
+
+// Encrypt encrypts plaintext with additionalData. The resulting ciphertext
+// consists of (nonce || ciphertext || tag).
+func (x *XChaCha20Poly1305) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.NewX(x.Key)
+	if err != nil {
+		return nil, fmt.Errorf("xchacha20poly1305: %v", err)
+	}
+	nonce := random.GetRandomBytes(uint32(aead.NonceSize()))
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// Decrypt decrypts ciphertext with additionalData.
+func (x *XChaCha20Poly1305) Decrypt(ciphertext, additionalData []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.NewX(x.Key)
+	if err != nil {
+		return nil, fmt.Errorf("xchacha20poly1305: %v", err)
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("xchacha20poly1305: ciphertext too short")
+	}
+	nonce := ciphertext[:aead.NonceSize()]
+	return aead.Open(nil, nonce, ciphertext[aead.NonceSize():], additionalData)
+}
@@ -0,0 +1,179 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package hybrid provides the subtle implementation of the hybrid
+// encryption primitive pairing an X25519 key exchange with
+// XChaCha20-Poly1305, in the style of HPKE's base mode.
+package hybrid
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/google/tink/go/subtle/random"
+)
+
+const x25519KeySize = 32
+
+// zeroNonce is safe here because the AEAD key is derived fresh for every
+// message (it binds the ephemeral public key and is never reused), so
+// XChaCha20-Poly1305's usual need for a random nonce per key is already
+// satisfied at the key-derivation layer.
+var zeroNonce = make([]byte, chacha20poly1305.NonceSizeX)
+
+// GeneratePrivateKey returns a new random X25519 private key.
+func GeneratePrivateKey() ([]byte, error) {
+	priv := random.GetRandomBytes(x25519KeySize)
+	// Clamp per RFC 7748; curve25519.X25519 does this internally as well,
+	// but clamping here keeps the stored private key canonical.
+	priv[0] &= 248
+	priv[31] &= 127
+	priv[31] |= 64
+	return priv, nil
+}
+
+// PublicFromPrivate derives the X25519 public key for priv.
+func PublicFromPrivate(priv []byte) ([]byte, error) {
+	return curve25519.X25519(priv, curve25519.Basepoint)
+}
+
+// XChaCha20Poly1305HKDFHybridEncrypt implements the tink.HybridEncrypt
+// interface for the recipient's X25519 public key.
+type XChaCha20Poly1305HKDFHybridEncrypt struct {
+	RecipientPublicKey []byte
+	ContextInfo        []byte
+}
+
+// NewXChaCha20Poly1305HKDFHybridEncrypt returns a new
+// XChaCha20Poly1305HKDFHybridEncrypt backed by the recipient's 32-byte
+// X25519 public key.
+func NewXChaCha20Poly1305HKDFHybridEncrypt(recipientPublicKey, contextInfo []byte) (*XChaCha20Poly1305HKDFHybridEncrypt, error) {
+	if len(recipientPublicKey) != x25519KeySize {
+		return nil, fmt.Errorf("xchacha20poly1305_hkdf_hybrid: invalid recipient public key size; want %d, got %d", x25519KeySize, len(recipientPublicKey))
+	}
+	return &XChaCha20Poly1305HKDFHybridEncrypt{
+		RecipientPublicKey: recipientPublicKey,
+		ContextInfo:        contextInfo,
+	}, nil
+}
+
+// Encrypt generates an ephemeral X25519 keypair, derives a single-use
+// XChaCha20-Poly1305 key via HKDF-SHA256 over the DH output, and seals
+// plaintext with it. The output is (eph_pub || ciphertext || tag).
+func (e *XChaCha20Poly1305HKDFHybridEncrypt) Encrypt(plaintext, contextInfo []byte) ([]byte, error) {
+	ephPriv, err := GeneratePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	ephPub, err := curve25519.X25519(ephPriv, curve25519.Basepoint)
+	if err != nil {
+		return nil, fmt.Errorf("xchacha20poly1305_hkdf_hybrid: failed to compute ephemeral public key: %v", err)
+	}
+	dh, err := curve25519.X25519(ephPriv, e.RecipientPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("xchacha20poly1305_hkdf_hybrid: key exchange failed: %v", err)
+	}
+	key, err := deriveKey(dh, ephPub, e.RecipientPublicKey, mergeInfo(e.ContextInfo, contextInfo))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+	sealed := aead.Seal(nil, zeroNonce, plaintext, nil)
+	out := make([]byte, 0, len(ephPub)+len(sealed))
+	out = append(out, ephPub...)
+	out = append(out, sealed...)
+	return out, nil
+}
+
+// XChaCha20Poly1305HKDFHybridDecrypt implements the tink.HybridDecrypt
+// interface for the recipient's X25519 private key.
+type XChaCha20Poly1305HKDFHybridDecrypt struct {
+	RecipientPrivateKey []byte
+	RecipientPublicKey  []byte
+	ContextInfo         []byte
+}
+
+// NewXChaCha20Poly1305HKDFHybridDecrypt returns a new
+// XChaCha20Poly1305HKDFHybridDecrypt backed by the recipient's 32-byte
+// X25519 private key and its matching public key.
+func NewXChaCha20Poly1305HKDFHybridDecrypt(recipientPrivateKey, recipientPublicKey, contextInfo []byte) (*XChaCha20Poly1305HKDFHybridDecrypt, error) {
+	if len(recipientPrivateKey) != x25519KeySize {
+		return nil, fmt.Errorf("xchacha20poly1305_hkdf_hybrid: invalid recipient private key size; want %d, got %d", x25519KeySize, len(recipientPrivateKey))
+	}
+	if len(recipientPublicKey) != x25519KeySize {
+		return nil, fmt.Errorf("xchacha20poly1305_hkdf_hybrid: invalid recipient public key size; want %d, got %d", x25519KeySize, len(recipientPublicKey))
+	}
+	return &XChaCha20Poly1305HKDFHybridDecrypt{
+		RecipientPrivateKey: recipientPrivateKey,
+		RecipientPublicKey:  recipientPublicKey,
+		ContextInfo:         contextInfo,
+	}, nil
+}
+
+// Decrypt recomputes the DH shared secret and the HKDF-derived key from the
+// ephemeral public key prefixed to ciphertext, then opens the sealed box.
+func (d *XChaCha20Poly1305HKDFHybridDecrypt) Decrypt(ciphertext, contextInfo []byte) ([]byte, error) {
+	if len(ciphertext) < x25519KeySize+chacha20poly1305.Overhead {
+		return nil, fmt.Errorf("xchacha20poly1305_hkdf_hybrid: ciphertext too short")
+	}
+	ephPub := ciphertext[:x25519KeySize]
+	sealed := ciphertext[x25519KeySize:]
+
+	dh, err := curve25519.X25519(d.RecipientPrivateKey, ephPub)
+	if err != nil {
+		return nil, fmt.Errorf("xchacha20poly1305_hkdf_hybrid: key exchange failed: %v", err)
+	}
+	key, err := deriveKey(dh, ephPub, d.RecipientPublicKey, mergeInfo(d.ContextInfo, contextInfo))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, zeroNonce, sealed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("xchacha20poly1305_hkdf_hybrid: decryption failed: %v", err)
+	}
+	return plaintext, nil
+}
+
+func mergeInfo(defaultInfo, callInfo []byte) []byte {
+	if len(callInfo) == 0 {
+		return defaultInfo
+	}
+	return append(append([]byte{}, defaultInfo...), callInfo...)
+}
+
+func deriveKey(dh, ephPub, recipientPub, info []byte) ([]byte, error) {
+	ikm := make([]byte, 0, len(dh)+len(ephPub)+len(recipientPub))
+	ikm = append(ikm, dh...)
+	ikm = append(ikm, ephPub...)
+	ikm = append(ikm, recipientPub...)
+
+	reader := hkdf.New(sha256.New, ikm, nil, info)
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(reader, key); err != nil {
+		return nil, fmt.Errorf("xchacha20poly1305_hkdf_hybrid: failed to derive key: %v", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,97 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/tink/go/subtle/hybrid"
+	"github.com/google/tink/go/subtle/random"
+)
+
+func TestXChaCha20Poly1305HKDFHybridRoundTrip(t *testing.T) {
+	priv, err := hybrid.GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("GeneratePrivateKey() = _, %v; want _, nil", err)
+	}
+	pub, err := hybrid.PublicFromPrivate(priv)
+	if err != nil {
+		t.Fatalf("PublicFromPrivate() = _, %v; want _, nil", err)
+	}
+
+	enc, err := hybrid.NewXChaCha20Poly1305HKDFHybridEncrypt(pub, []byte("default info"))
+	if err != nil {
+		t.Fatalf("NewXChaCha20Poly1305HKDFHybridEncrypt() = _, %v; want _, nil", err)
+	}
+	dec, err := hybrid.NewXChaCha20Poly1305HKDFHybridDecrypt(priv, pub, []byte("default info"))
+	if err != nil {
+		t.Fatalf("NewXChaCha20Poly1305HKDFHybridDecrypt() = _, %v; want _, nil", err)
+	}
+
+	pt := random.GetRandomBytes(64)
+	ct, err := enc.Encrypt(pt, []byte("per-call context"))
+	if err != nil {
+		t.Fatalf("Encrypt() = _, %v; want _, nil", err)
+	}
+	got, err := dec.Decrypt(ct, []byte("per-call context"))
+	if err != nil {
+		t.Fatalf("Decrypt() = _, %v; want _, nil", err)
+	}
+	if !bytes.Equal(got, pt) {
+		t.Errorf("decrypted plaintext does not match original")
+	}
+}
+
+func TestXChaCha20Poly1305HKDFHybridWrongContextInfoFails(t *testing.T) {
+	priv, _ := hybrid.GeneratePrivateKey()
+	pub, _ := hybrid.PublicFromPrivate(priv)
+	enc, _ := hybrid.NewXChaCha20Poly1305HKDFHybridEncrypt(pub, nil)
+	dec, _ := hybrid.NewXChaCha20Poly1305HKDFHybridDecrypt(priv, pub, nil)
+
+	ct, err := enc.Encrypt(random.GetRandomBytes(32), []byte("context-a"))
+	if err != nil {
+		t.Fatalf("Encrypt() = _, %v; want _, nil", err)
+	}
+	if _, err := dec.Decrypt(ct, []byte("context-b")); err == nil {
+		t.Errorf("Decrypt() with wrong context info = _, nil; want _, err")
+	}
+}
+
+func TestXChaCha20Poly1305HKDFHybridWrongPrivateKeyFails(t *testing.T) {
+	priv1, _ := hybrid.GeneratePrivateKey()
+	pub1, _ := hybrid.PublicFromPrivate(priv1)
+	priv2, _ := hybrid.GeneratePrivateKey()
+
+	enc, _ := hybrid.NewXChaCha20Poly1305HKDFHybridEncrypt(pub1, nil)
+	dec, _ := hybrid.NewXChaCha20Poly1305HKDFHybridDecrypt(priv2, pub1, nil)
+
+	ct, err := enc.Encrypt(random.GetRandomBytes(32), nil)
+	if err != nil {
+		t.Fatalf("Encrypt() = _, %v; want _, nil", err)
+	}
+	if _, err := dec.Decrypt(ct, nil); err == nil {
+		t.Errorf("Decrypt() with mismatched private key = _, nil; want _, err")
+	}
+}
+
+func TestNewXChaCha20Poly1305HKDFHybridInvalidKeySizes(t *testing.T) {
+	if _, err := hybrid.NewXChaCha20Poly1305HKDFHybridEncrypt(random.GetRandomBytes(31), nil); err == nil {
+		t.Errorf("NewXChaCha20Poly1305HKDFHybridEncrypt() with bad public key size = nil; want err")
+	}
+	if _, err := hybrid.NewXChaCha20Poly1305HKDFHybridDecrypt(random.GetRandomBytes(31), random.GetRandomBytes(32), nil); err == nil {
+		t.Errorf("NewXChaCha20Poly1305HKDFHybridDecrypt() with bad private key size = nil; want err")
+	}
+}
@@ -0,0 +1,296 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package streamingaead provides the subtle implementation of the streaming
+// AEAD primitive backed by XChaCha20-Poly1305 segments.
+package streamingaead
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/google/tink/go/subtle/random"
+)
+
+const (
+	// xChaCha20Poly1305HKDFSaltSize is the size, in bytes, of the random salt
+	// placed in the stream header and mixed into the per-stream key.
+	xChaCha20Poly1305HKDFSaltSize = 32
+	// xChaCha20Poly1305HKDFNoncePrefixSize is the size, in bytes, of the
+	// random nonce prefix placed in the stream header. The remaining 5 bytes
+	// of the 24-byte XChaCha20-Poly1305 nonce are a big-endian segment
+	// counter (4 bytes) and a last-segment flag (1 byte).
+	xChaCha20Poly1305HKDFNoncePrefixSize = chacha20poly1305.NonceSizeX - 5
+
+	minSegmentSize = xChaCha20Poly1305HKDFSaltSize + xChaCha20Poly1305HKDFNoncePrefixSize + chacha20poly1305.Overhead + 1
+
+	notLastSegment byte = 0
+	lastSegment    byte = 1
+)
+
+// XChaCha20Poly1305HKDFStream implements streaming encryption/decryption of
+// arbitrarily large plaintexts as a sequence of fixed-size
+// XChaCha20-Poly1305 segments. Every segment is sealed with the same
+// HKDF-SHA256-derived per-stream key; what makes segments distinct (and
+// binds them to their position and to the end of the stream) is the nonce,
+// which embeds a big-endian segment counter and a last-segment flag.
+type XChaCha20Poly1305HKDFStream struct {
+	MainKey     []byte
+	SegmentSize int
+}
+
+// NewXChaCha20Poly1305HKDFStream creates a new XChaCha20Poly1305HKDFStream
+// backed by mainKey (32 bytes) and using segmentSize-byte ciphertext
+// segments.
+func NewXChaCha20Poly1305HKDFStream(mainKey []byte, segmentSize int) (*XChaCha20Poly1305HKDFStream, error) {
+	if len(mainKey) != chacha20poly1305.KeySize {
+		return nil, fmt.Errorf("streamingaead: invalid main key size; want %d, got %d", chacha20poly1305.KeySize, len(mainKey))
+	}
+	if segmentSize < minSegmentSize {
+		return nil, fmt.Errorf("streamingaead: segment size too small; want >= %d, got %d", minSegmentSize, segmentSize)
+	}
+	return &XChaCha20Poly1305HKDFStream{MainKey: mainKey, SegmentSize: segmentSize}, nil
+}
+
+// NewEncryptingWriter returns a writer that encrypts its input and writes
+// the resulting header and ciphertext segments to dst. aad is bound to
+// every segment and must be supplied again, identically, to
+// NewDecryptingReader.
+func (s *XChaCha20Poly1305HKDFStream) NewEncryptingWriter(dst io.Writer, aad []byte) (io.WriteCloser, error) {
+	salt := random.GetRandomBytes(xChaCha20Poly1305HKDFSaltSize)
+	noncePrefix := random.GetRandomBytes(xChaCha20Poly1305HKDFNoncePrefixSize)
+	header := buildHeader(salt, noncePrefix, s.SegmentSize)
+	if _, err := dst.Write(header); err != nil {
+		return nil, fmt.Errorf("streamingaead: failed to write header: %v", err)
+	}
+	aead, err := newAEAD(s.MainKey, salt)
+	if err != nil {
+		return nil, err
+	}
+	plaintextSegmentSize := s.SegmentSize - chacha20poly1305.Overhead
+	return &encryptWriter{
+		dst:                  dst,
+		aead:                 aead,
+		noncePrefix:          noncePrefix,
+		aad:                  append([]byte{}, aad...),
+		header:               header,
+		plaintextSegmentSize: plaintextSegmentSize,
+		buf:                  make([]byte, 0, plaintextSegmentSize),
+	}, nil
+}
+
+// NewDecryptingReader returns a reader that reads the header and ciphertext
+// segments written by NewEncryptingWriter from src and yields the decrypted
+// plaintext. aad must match the value passed to NewEncryptingWriter.
+func (s *XChaCha20Poly1305HKDFStream) NewDecryptingReader(src io.Reader, aad []byte) (io.Reader, error) {
+	return &decryptReader{
+		mainKey: s.MainKey,
+		src:     bufio.NewReader(src),
+		aad:     append([]byte{}, aad...),
+	}, nil
+}
+
+func buildHeader(salt, noncePrefix []byte, segmentSize int) []byte {
+	header := make([]byte, 0, len(salt)+len(noncePrefix)+binary.MaxVarintLen64)
+	header = append(header, salt...)
+	header = append(header, noncePrefix...)
+	var sizeBuf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(sizeBuf[:], uint64(segmentSize))
+	header = append(header, sizeBuf[:n]...)
+	return header
+}
+
+func newAEAD(mainKey, salt []byte) (cipher.AEAD, error) {
+	reader := hkdf.New(sha256.New, mainKey, salt, nil)
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(reader, key); err != nil {
+		return nil, fmt.Errorf("streamingaead: failed to derive segment key: %v", err)
+	}
+	return chacha20poly1305.NewX(key)
+}
+
+func segmentNonce(noncePrefix []byte, counter uint32, isLast bool) []byte {
+	nonce := make([]byte, 0, chacha20poly1305.NonceSizeX)
+	nonce = append(nonce, noncePrefix...)
+	var ctr [4]byte
+	binary.BigEndian.PutUint32(ctr[:], counter)
+	nonce = append(nonce, ctr[:]...)
+	if isLast {
+		nonce = append(nonce, lastSegment)
+	} else {
+		nonce = append(nonce, notLastSegment)
+	}
+	return nonce
+}
+
+// segmentAAD binds the caller-supplied aad to the stream header, so that
+// tampering with the header invalidates every segment.
+func segmentAAD(aad, header []byte) []byte {
+	out := make([]byte, 0, len(aad)+len(header))
+	out = append(out, aad...)
+	out = append(out, header...)
+	return out
+}
+
+type encryptWriter struct {
+	dst                  io.Writer
+	aead                 cipher.AEAD
+	noncePrefix          []byte
+	aad                  []byte
+	header               []byte
+	plaintextSegmentSize int
+	buf                  []byte
+	segmentCounter       uint32
+	closed               bool
+}
+
+func (w *encryptWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, fmt.Errorf("streamingaead: write on closed writer")
+	}
+	written := 0
+	for len(p) > 0 {
+		n := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+n]
+		p = p[n:]
+		written += n
+		if len(w.buf) == w.plaintextSegmentSize {
+			if err := w.flushSegment(false); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (w *encryptWriter) flushSegment(isLast bool) error {
+	nonce := segmentNonce(w.noncePrefix, w.segmentCounter, isLast)
+	ct := w.aead.Seal(nil, nonce, w.buf, segmentAAD(w.aad, w.header))
+	if _, err := w.dst.Write(ct); err != nil {
+		return fmt.Errorf("streamingaead: failed to write segment: %v", err)
+	}
+	w.segmentCounter++
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// Close seals and writes the final segment. It must be called exactly once,
+// even for empty plaintexts, since the last-segment flag is what tells the
+// reader where the stream legitimately ends.
+func (w *encryptWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.flushSegment(true)
+}
+
+type decryptReader struct {
+	mainKey      []byte
+	src          *bufio.Reader
+	aad          []byte
+	header       []byte
+	aead         cipher.AEAD
+	noncePrefix  []byte
+	segmentSize  int
+	segCounter   uint32
+	plaintextBuf []byte
+	pos          int
+	done         bool
+	headerRead   bool
+}
+
+func (r *decryptReader) readHeader() error {
+	salt := make([]byte, xChaCha20Poly1305HKDFSaltSize)
+	if _, err := io.ReadFull(r.src, salt); err != nil {
+		return fmt.Errorf("streamingaead: failed to read salt: %v", err)
+	}
+	noncePrefix := make([]byte, xChaCha20Poly1305HKDFNoncePrefixSize)
+	if _, err := io.ReadFull(r.src, noncePrefix); err != nil {
+		return fmt.Errorf("streamingaead: failed to read nonce prefix: %v", err)
+	}
+	segmentSize, err := binary.ReadUvarint(r.src)
+	if err != nil {
+		return fmt.Errorf("streamingaead: failed to read segment size: %v", err)
+	}
+	aead, err := newAEAD(r.mainKey, salt)
+	if err != nil {
+		return err
+	}
+	r.header = buildHeader(salt, noncePrefix, int(segmentSize))
+	r.aead = aead
+	r.noncePrefix = noncePrefix
+	r.segmentSize = int(segmentSize)
+	r.headerRead = true
+	return nil
+}
+
+func (r *decryptReader) readSegment() error {
+	buf := make([]byte, r.segmentSize)
+	n, err := io.ReadFull(r.src, buf)
+	isLast := false
+	switch {
+	case err == nil:
+		if _, peekErr := r.src.Peek(1); peekErr != nil {
+			isLast = true
+		}
+	case err == io.ErrUnexpectedEOF:
+		isLast = true
+		buf = buf[:n]
+	case err == io.EOF:
+		// The stream ended before a segment carrying the last-segment flag.
+		return fmt.Errorf("streamingaead: stream truncated: missing last segment")
+	default:
+		return fmt.Errorf("streamingaead: failed to read segment: %v", err)
+	}
+
+	nonce := segmentNonce(r.noncePrefix, r.segCounter, isLast)
+	pt, err := r.aead.Open(nil, nonce, buf, segmentAAD(r.aad, r.header))
+	if err != nil {
+		return fmt.Errorf("streamingaead: segment %d failed to authenticate: %v", r.segCounter, err)
+	}
+	r.segCounter++
+	r.plaintextBuf = pt
+	r.pos = 0
+	if isLast {
+		r.done = true
+	}
+	return nil
+}
+
+func (r *decryptReader) Read(p []byte) (int, error) {
+	if !r.headerRead {
+		if err := r.readHeader(); err != nil {
+			return 0, err
+		}
+	}
+	for r.pos >= len(r.plaintextBuf) {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.readSegment(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.plaintextBuf[r.pos:])
+	r.pos += n
+	return n, nil
+}
@@ -0,0 +1,206 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/tink/go/subtle/random"
+	streamingaead "github.com/google/tink/go/subtle/streamingaead"
+)
+
+func newTestStream(t *testing.T, segmentSize int) *streamingaead.XChaCha20Poly1305HKDFStream {
+	t.Helper()
+	s, err := streamingaead.NewXChaCha20Poly1305HKDFStream(random.GetRandomBytes(32), segmentSize)
+	if err != nil {
+		t.Fatalf("NewXChaCha20Poly1305HKDFStream() = _, %v; want _, nil", err)
+	}
+	return s
+}
+
+func encryptToBytes(t *testing.T, s *streamingaead.XChaCha20Poly1305HKDFStream, pt, aad []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := s.NewEncryptingWriter(&buf, aad)
+	if err != nil {
+		t.Fatalf("NewEncryptingWriter() = _, %v; want _, nil", err)
+	}
+	if _, err := w.Write(pt); err != nil {
+		t.Fatalf("w.Write() = _, %v; want _, nil", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("w.Close() = %v; want nil", err)
+	}
+	return buf.Bytes()
+}
+
+func TestXChaCha20Poly1305HKDFStreamRoundTrip(t *testing.T) {
+	for _, size := range []int{0, 1, 100, 4096, 4096*3 + 17} {
+		s := newTestStream(t, 256)
+		pt := random.GetRandomBytes(uint32(size))
+		aad := random.GetRandomBytes(16)
+		ct := encryptToBytes(t, s, pt, aad)
+
+		r, err := s.NewDecryptingReader(bytes.NewReader(ct), aad)
+		if err != nil {
+			t.Fatalf("NewDecryptingReader() = _, %v; want _, nil", err)
+		}
+		got, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Fatalf("ReadAll() = _, %v; want _, nil (size=%d)", err, size)
+		}
+		if !bytes.Equal(got, pt) {
+			t.Errorf("decrypted plaintext mismatch for size=%d", size)
+		}
+	}
+}
+
+func TestXChaCha20Poly1305HKDFStreamThroughPipe(t *testing.T) {
+	s := newTestStream(t, 512)
+	pt := random.GetRandomBytes(1 << 16)
+	aad := random.GetRandomBytes(8)
+
+	pr, pw := io.Pipe()
+	w, err := s.NewEncryptingWriter(pw, aad)
+	if err != nil {
+		t.Fatalf("NewEncryptingWriter() = _, %v; want _, nil", err)
+	}
+	go func() {
+		if _, err := w.Write(pt); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		if err := w.Close(); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		pw.Close()
+	}()
+
+	r, err := s.NewDecryptingReader(pr, aad)
+	if err != nil {
+		t.Fatalf("NewDecryptingReader() = _, %v; want _, nil", err)
+	}
+	got, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll() = _, %v; want _, nil", err)
+	}
+	if !bytes.Equal(got, pt) {
+		t.Errorf("decrypted plaintext does not match original")
+	}
+}
+
+func TestXChaCha20Poly1305HKDFStreamChunkBoundaryIndependence(t *testing.T) {
+	// Writing the plaintext in 7-byte chunks, which do not line up with the
+	// segment boundaries, must still produce a decryptable stream.
+	s := newTestStream(t, 256)
+	pt := random.GetRandomBytes(4096)
+	aad := random.GetRandomBytes(4)
+
+	var buf bytes.Buffer
+	w, err := s.NewEncryptingWriter(&buf, aad)
+	if err != nil {
+		t.Fatalf("NewEncryptingWriter() = _, %v; want _, nil", err)
+	}
+	for i := 0; i < len(pt); i += 7 {
+		end := i + 7
+		if end > len(pt) {
+			end = len(pt)
+		}
+		if _, err := w.Write(pt[i:end]); err != nil {
+			t.Fatalf("w.Write() = _, %v; want _, nil", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("w.Close() = %v; want nil", err)
+	}
+
+	r, err := s.NewDecryptingReader(bytes.NewReader(buf.Bytes()), aad)
+	if err != nil {
+		t.Fatalf("NewDecryptingReader() = _, %v; want _, nil", err)
+	}
+	got, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll() = _, %v; want _, nil", err)
+	}
+	if !bytes.Equal(got, pt) {
+		t.Errorf("decrypted plaintext does not match original after chunked writes")
+	}
+}
+
+func TestXChaCha20Poly1305HKDFStreamRejectsBitFlipInSegment(t *testing.T) {
+	s := newTestStream(t, 256)
+	pt := random.GetRandomBytes(1024)
+	aad := random.GetRandomBytes(4)
+	ct := encryptToBytes(t, s, pt, aad)
+
+	headerLen := 32 + (24 - 5) + 2 // salt + noncePrefix + 2-byte varint segment size (256)
+	for _, pos := range []int{headerLen, headerLen + 1, len(ct) - 1} {
+		corrupted := append([]byte{}, ct...)
+		corrupted[pos] ^= 0x01
+		r, err := s.NewDecryptingReader(bytes.NewReader(corrupted), aad)
+		if err != nil {
+			t.Fatalf("NewDecryptingReader() = _, %v; want _, nil", err)
+		}
+		if _, err := ioutil.ReadAll(r); err == nil {
+			t.Errorf("ReadAll() with bit flipped at %d = nil; want err", pos)
+		}
+	}
+}
+
+func TestXChaCha20Poly1305HKDFStreamRejectsBitFlipInHeader(t *testing.T) {
+	s := newTestStream(t, 256)
+	pt := random.GetRandomBytes(128)
+	aad := random.GetRandomBytes(4)
+	ct := encryptToBytes(t, s, pt, aad)
+
+	corrupted := append([]byte{}, ct...)
+	corrupted[0] ^= 0x01 // flip a bit in the salt
+	r, err := s.NewDecryptingReader(bytes.NewReader(corrupted), aad)
+	if err != nil {
+		t.Fatalf("NewDecryptingReader() = _, %v; want _, nil", err)
+	}
+	if _, err := ioutil.ReadAll(r); err == nil {
+		t.Errorf("ReadAll() with corrupted header = nil; want err")
+	}
+}
+
+func TestXChaCha20Poly1305HKDFStreamRejectsTruncation(t *testing.T) {
+	s := newTestStream(t, 256)
+	pt := random.GetRandomBytes(4096)
+	aad := random.GetRandomBytes(4)
+	ct := encryptToBytes(t, s, pt, aad)
+
+	truncated := ct[:len(ct)-10]
+	r, err := s.NewDecryptingReader(bytes.NewReader(truncated), aad)
+	if err != nil {
+		t.Fatalf("NewDecryptingReader() = _, %v; want _, nil", err)
+	}
+	if _, err := ioutil.ReadAll(r); err == nil {
+		t.Errorf("ReadAll() on truncated ciphertext = nil; want err")
+	}
+}
+
+func TestNewXChaCha20Poly1305HKDFStreamInvalidArgs(t *testing.T) {
+	if _, err := streamingaead.NewXChaCha20Poly1305HKDFStream(random.GetRandomBytes(16), 512); err == nil {
+		t.Errorf("NewXChaCha20Poly1305HKDFStream() with bad key size = nil; want err")
+	}
+	if _, err := streamingaead.NewXChaCha20Poly1305HKDFStream(random.GetRandomBytes(32), 8); err == nil {
+		t.Errorf("NewXChaCha20Poly1305HKDFStream() with too-small segment size = nil; want err")
+	}
+}